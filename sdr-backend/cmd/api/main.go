@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -10,9 +14,16 @@ import (
 
 	"github.com/camwick/sdr-backend/internal/config"
 	"github.com/camwick/sdr-backend/internal/handlers"
+	"github.com/camwick/sdr-backend/internal/services/catalog"
 	"github.com/camwick/sdr-backend/internal/services/database"
+	"github.com/camwick/sdr-backend/internal/services/database/postgres"
+	"github.com/camwick/sdr-backend/internal/services/database/sqlite"
+	"github.com/camwick/sdr-backend/internal/services/database/supabase"
+	"github.com/camwick/sdr-backend/internal/services/ingest"
+	"github.com/camwick/sdr-backend/internal/services/jobs"
 	"github.com/camwick/sdr-backend/internal/services/parser"
-	"github.com/camwick/sdr-backend/internal/services/tiktok"
+	"github.com/camwick/sdr-backend/internal/services/pipeline"
+	"github.com/camwick/sdr-backend/internal/services/source"
 	"github.com/camwick/sdr-backend/internal/services/transcription"
 )
 
@@ -23,25 +34,35 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Validate required config
-	if cfg.GroqAPIKey == "" {
-		log.Fatal("GROQ_API_KEY is required")
-	}
-	if cfg.ClaudeAPIKey == "" {
-		log.Fatal("CLAUDE_API_KEY is required")
-	}
-	if cfg.SupabaseURL == "" || cfg.SupabaseAnonKey == "" {
-		log.Fatal("SUPABASE_URL and SUPABASE_ANON_KEY are required")
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
 	// Initialize services
-	tiktokSvc := tiktok.NewService()
-	transcriptionSvc := transcription.NewService(cfg.GroqAPIKey)
-	parserSvc := parser.NewService(cfg.ClaudeAPIKey)
-	dbSvc := database.NewService(cfg.SupabaseURL, cfg.SupabaseAnonKey)
+	sources := source.Default()
+	uploads := source.NewUploads(int64(cfg.UploadMaxMB)<<20, time.Duration(cfg.UploadMaxSeconds)*time.Second)
+	transcriber, err := newTranscriber(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize transcription: %v", err)
+	}
+	recipeParser, err := newParser(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize parser: %v", err)
+	}
+	repo, err := newRepository(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	deviceCatalog := catalog.Default()
+	pipelineSvc := pipeline.NewService(sources, uploads, transcriber, recipeParser, deviceCatalog, repo)
+	jobSvc := jobs.NewService(cfg.JobWorkers, cfg.JobQueueSize, pipelineSvc.Run)
+	ingestSvc := ingest.NewService(sources.TikTok(), jobSvc, repo, cfg.IngestMaxVideos, cfg.IngestConcurrency)
 
 	// Initialize handlers
-	h := handlers.NewHandler(tiktokSvc, transcriptionSvc, parserSvc, dbSvc)
+	h := handlers.NewHandler(sources, uploads, repo, jobSvc, ingestSvc, deviceCatalog)
 
 	// Setup router
 	r := chi.NewRouter()
@@ -61,6 +82,21 @@ func main() {
 	// Routes
 	r.Get("/health", h.HealthCheck)
 	r.Post("/api/transcribe", h.Transcribe)
+	r.Post("/api/transcribe/upload", h.TranscribeUpload)
+	r.Get("/api/jobs/{id}", h.GetJob)
+	r.Get("/api/jobs/{id}/events", h.JobEvents)
+	r.Get("/api/recipes", h.ListRecipes)
+	r.Get("/api/recipes/search", h.SearchRecipes)
+	r.Get("/api/recipes/export", h.ExportRecipes)
+	r.Get("/api/recipes/{id}", h.GetRecipe)
+	r.Get("/api/recipes/{id}/export", h.ExportRecipe)
+	r.Get("/api/recipes/{id}/export/ableton", h.ExportAbleton)
+	r.Get("/api/recipes/{id}/export/ableton/report", h.ExportAbletonReport)
+	r.Get("/api/recipes/{id}/export/vital", h.ExportVital)
+	r.Get("/api/recipes/{id}/export/vital/report", h.ExportVitalReport)
+	r.Get("/api/devices", h.ListDevices)
+	r.Post("/api/creators/{handle}/ingest", h.IngestCreator)
+	r.Get("/api/creators/{handle}/ingest", h.GetCreatorIngest)
 
 	// Start server
 	log.Printf("SDR Backend starting on port %s", cfg.Port)
@@ -68,3 +104,76 @@ func main() {
 		log.Fatalf("Server failed: %v", err)
 	}
 }
+
+// newRepository creates the storage backend selected by DATABASE_BACKEND
+func newRepository(ctx context.Context, cfg *config.Config) (database.Repository, error) {
+	switch cfg.DatabaseBackend {
+	case "supabase":
+		if cfg.SupabaseURL == "" || cfg.SupabaseAnonKey == "" {
+			return nil, fmt.Errorf("SUPABASE_URL and SUPABASE_ANON_KEY are required")
+		}
+		return supabase.NewService(cfg.SupabaseURL, cfg.SupabaseAnonKey), nil
+	case "postgres":
+		if cfg.DatabaseURL == "" {
+			return nil, fmt.Errorf("DATABASE_URL is required")
+		}
+		return postgres.NewService(ctx, cfg.DatabaseURL)
+	case "sqlite":
+		return sqlite.NewService(ctx, cfg.SQLitePath)
+	default:
+		return nil, fmt.Errorf("unknown DATABASE_BACKEND %q", cfg.DatabaseBackend)
+	}
+}
+
+// newParser creates the LLM provider selected by PARSER_PROVIDER, with
+// recipe validation on top
+func newParser(cfg *config.Config) (parser.RecipeParser, error) {
+	switch cfg.ParserProvider {
+	case "anthropic":
+		if cfg.ClaudeAPIKey == "" {
+			return nil, fmt.Errorf("CLAUDE_API_KEY is required")
+		}
+		return parser.NewValidated(
+			parser.NewAnthropic(cfg.ClaudeAPIKey, cfg.ParserBaseURL, cfg.ParserModel, cfg.ParserMaxTokens),
+		), nil
+	case "openai":
+		// Local servers such as Ollama don't need a key
+		if cfg.OpenAIAPIKey == "" && cfg.ParserBaseURL == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is required unless PARSER_BASE_URL points at a local server")
+		}
+		return parser.NewValidated(
+			parser.NewOpenAI(cfg.OpenAIAPIKey, cfg.ParserBaseURL, cfg.ParserModel, cfg.ParserMaxTokens),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown PARSER_PROVIDER %q", cfg.ParserProvider)
+	}
+}
+
+// newTranscriber creates the transcription provider selected by TRANSCRIPTION_PROVIDER
+func newTranscriber(cfg *config.Config) (transcription.Transcriber, error) {
+	switch cfg.TranscriptionProvider {
+	case "groq":
+		if cfg.GroqAPIKey == "" {
+			return nil, fmt.Errorf("GROQ_API_KEY is required")
+		}
+		// Groq rejects uploads over 25 MB, so long videos are split first
+		var transcriber transcription.Transcriber = transcription.NewChunked(
+			transcription.NewGroq(cfg.GroqAPIKey),
+			int64(cfg.TranscriptionMaxUploadMB)<<20,
+			cfg.TranscriptionChunkSeconds,
+			cfg.TranscriptionChunkOverlap,
+			cfg.TranscriptionChunkConcurrency,
+		)
+		if cfg.TranscriptionTranscode {
+			transcriber = transcription.NewTranscoded(transcriber, cfg.TranscriptionTranscodeBitrate)
+		}
+		return transcriber, nil
+	case "whisper-cpp":
+		if cfg.WhisperCppModel == "" {
+			return nil, fmt.Errorf("WHISPER_CPP_MODEL is required")
+		}
+		return transcription.NewWhisperCpp(cfg.WhisperCppBinary, cfg.WhisperCppModel, cfg.WhisperLanguage), nil
+	default:
+		return nil, fmt.Errorf("unknown TRANSCRIPTION_PROVIDER %q", cfg.TranscriptionProvider)
+	}
+}
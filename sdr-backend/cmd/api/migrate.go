@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/camwick/sdr-backend/internal/config"
+	"github.com/camwick/sdr-backend/internal/migrations"
+	"github.com/camwick/sdr-backend/internal/services/database/postgres"
+	"github.com/camwick/sdr-backend/internal/services/database/sqlite"
+)
+
+const migrateUsage = `usage: sdr-backend migrate up|down|status
+
+  up      apply all pending migrations
+  down    roll back the most recently applied migration
+  status  list migrations and when they were applied
+
+The database is chosen by DATABASE_BACKEND: sqlite uses SQLITE_PATH,
+postgres and supabase use DATABASE_URL (for Supabase, the direct
+Postgres connection string from Settings > Database).`
+
+// runMigrate implements the migrate subcommand
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	db, dialect, err := openMigrationDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.New(db, dialect)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+
+	case "down":
+		migration, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if migration == nil {
+			fmt.Println("no migrations to roll back")
+		} else {
+			fmt.Printf("rolled back %04d_%s\n", migration.Version, migration.Name)
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, applied)
+		}
+		w.Flush()
+
+	default:
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	return nil
+}
+
+// openMigrationDB connects to the configured backend's database
+func openMigrationDB(cfg *config.Config) (*sql.DB, string, error) {
+	switch cfg.DatabaseBackend {
+	case "sqlite":
+		db, err := sqlite.Open(cfg.SQLitePath)
+		return db, migrations.SQLite, err
+	case "postgres", "supabase":
+		if cfg.DatabaseURL == "" {
+			return nil, "", fmt.Errorf("DATABASE_URL is required to run migrations")
+		}
+		db, err := postgres.OpenDB(cfg.DatabaseURL)
+		return db, migrations.Postgres, err
+	default:
+		return nil, "", fmt.Errorf("unknown DATABASE_BACKEND %q", cfg.DatabaseBackend)
+	}
+}
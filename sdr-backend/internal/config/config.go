@@ -2,16 +2,57 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port            string
-	GroqAPIKey      string
+	Port string
+
+	// Transcription
+	TranscriptionProvider string // groq or whisper-cpp
+	GroqAPIKey            string
+	WhisperCppBinary      string
+	WhisperCppModel       string
+	WhisperLanguage       string
+
+	// Pre-upload transcode to mono 16 kHz Opus
+	TranscriptionTranscode        bool
+	TranscriptionTranscodeBitrate string
+
+	// Chunking for files over the provider's upload limit
+	TranscriptionMaxUploadMB      int
+	TranscriptionChunkSeconds     int
+	TranscriptionChunkOverlap     int // seconds
+	TranscriptionChunkConcurrency int
+
+	// Parsing
+	ParserProvider  string // anthropic or openai
 	ClaudeAPIKey    string
+	OpenAIAPIKey    string
+	ParserModel     string // empty uses the provider's default
+	ParserMaxTokens int
+	ParserBaseURL   string // empty uses the provider's public API
+
+	// Storage
+	DatabaseBackend string // supabase, postgres or sqlite
 	SupabaseURL     string
 	SupabaseAnonKey string
+	DatabaseURL     string
+	SQLitePath      string
+
+	// Background jobs
+	JobWorkers   int
+	JobQueueSize int
+
+	// Limits for POST /api/transcribe/upload
+	UploadMaxMB      int
+	UploadMaxSeconds int
+
+	// POST /api/creators/{handle}/ingest
+	IngestMaxVideos   int
+	IngestConcurrency int // one creator's videos transcribed at once
 }
 
 func Load() (*Config, error) {
@@ -19,11 +60,43 @@ func Load() (*Config, error) {
 	godotenv.Load()
 
 	return &Config{
-		Port:            getEnv("PORT", "8080"),
-		GroqAPIKey:      os.Getenv("GROQ_API_KEY"),
+		Port: getEnv("PORT", "8080"),
+
+		TranscriptionProvider: getEnv("TRANSCRIPTION_PROVIDER", "groq"),
+		GroqAPIKey:            os.Getenv("GROQ_API_KEY"),
+		WhisperCppBinary:      getEnv("WHISPER_CPP_BINARY", "whisper-cli"),
+		WhisperCppModel:       os.Getenv("WHISPER_CPP_MODEL"),
+		WhisperLanguage:       getEnv("WHISPER_LANGUAGE", "auto"),
+
+		TranscriptionTranscode:        getEnvBool("TRANSCRIPTION_TRANSCODE", true),
+		TranscriptionTranscodeBitrate: getEnv("TRANSCRIPTION_TRANSCODE_BITRATE", "24k"),
+
+		TranscriptionMaxUploadMB:      getEnvInt("TRANSCRIPTION_MAX_UPLOAD_MB", 24),
+		TranscriptionChunkSeconds:     getEnvInt("TRANSCRIPTION_CHUNK_SECONDS", 600),
+		TranscriptionChunkOverlap:     getEnvNonNegativeInt("TRANSCRIPTION_CHUNK_OVERLAP_SECONDS", 5),
+		TranscriptionChunkConcurrency: getEnvInt("TRANSCRIPTION_CHUNK_CONCURRENCY", 3),
+
+		ParserProvider:  getEnv("PARSER_PROVIDER", "anthropic"),
 		ClaudeAPIKey:    os.Getenv("CLAUDE_API_KEY"),
+		OpenAIAPIKey:    os.Getenv("OPENAI_API_KEY"),
+		ParserModel:     os.Getenv("PARSER_MODEL"),
+		ParserMaxTokens: getEnvInt("PARSER_MAX_TOKENS", 2048),
+		ParserBaseURL:   os.Getenv("PARSER_BASE_URL"),
+
+		DatabaseBackend: getEnv("DATABASE_BACKEND", "supabase"),
 		SupabaseURL:     os.Getenv("SUPABASE_URL"),
 		SupabaseAnonKey: os.Getenv("SUPABASE_ANON_KEY"),
+		DatabaseURL:     os.Getenv("DATABASE_URL"),
+		SQLitePath:      getEnv("SQLITE_PATH", "sdr.db"),
+
+		JobWorkers:   getEnvInt("JOB_WORKERS", 2),
+		JobQueueSize: getEnvInt("JOB_QUEUE_SIZE", 100),
+
+		UploadMaxMB:      getEnvInt("UPLOAD_MAX_MB", 200),
+		UploadMaxSeconds: getEnvInt("UPLOAD_MAX_SECONDS", 600),
+
+		IngestMaxVideos:   getEnvInt("INGEST_MAX_VIDEOS", 100),
+		IngestConcurrency: getEnvInt("INGEST_CONCURRENCY", 1),
 	}, nil
 }
 
@@ -33,3 +106,25 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// getEnvNonNegativeInt is getEnvInt for settings where 0 turns something off
+func getEnvNonNegativeInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/camwick/sdr-backend/internal/services/ingest"
+)
+
+// IngestCreator lists the creator's TikTok profile (the most recent
+// ?limit= videos, up to the configured maximum) and transcribes every video
+// that isn't already a tutorial. It returns once the videos are listed;
+// GetCreatorIngest reports progress.
+func (h *Handler) IngestCreator(w http.ResponseWriter, r *http.Request) {
+	handle := strings.TrimPrefix(chi.URLParam(r, "handle"), "@")
+	if !handlePattern.MatchString(handle) {
+		respondError(w, http.StatusBadRequest, "Invalid creator handle")
+		return
+	}
+
+	limit := h.ingest.MaxVideos()
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > h.ingest.MaxVideos() {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", h.ingest.MaxVideos()))
+			return
+		}
+		limit = n
+	}
+
+	result, err := h.ingest.Start(r.Context(), handle, limit)
+	if errors.Is(err, ingest.ErrInProgress) {
+		respondError(w, http.StatusConflict, "This creator is already being ingested")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to list videos for @%s: %v", handle, err)
+		respondError(w, http.StatusBadGateway, "Failed to list the creator's videos")
+		return
+	}
+
+	w.Header().Set("Location", "/api/creators/"+handle+"/ingest")
+	respondJSON(w, http.StatusAccepted, result)
+}
+
+// GetCreatorIngest reports the progress of the creator's latest ingest
+func (h *Handler) GetCreatorIngest(w http.ResponseWriter, r *http.Request) {
+	handle := strings.TrimPrefix(chi.URLParam(r, "handle"), "@")
+
+	result, err := h.ingest.Get(handle)
+	if err != nil {
+		respondError(w, http.StatusNotFound, "No ingest for this creator")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
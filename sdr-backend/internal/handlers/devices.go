@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// ListDevices returns the device catalog with how many recipes use each
+// device, most used first. Device names found in recipes but missing from
+// the catalog are included with catalogued set to false. Accepts ?status=
+// like the recipe endpoints and ?category= to filter.
+func (h *Handler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	status, err := statusFromQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	category := r.URL.Query().Get("category")
+
+	counts, err := h.db.CountRecipesByDevice(r.Context(), status)
+	if err != nil {
+		log.Printf("Failed to count recipes by device: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to load devices")
+		return
+	}
+
+	devices := h.catalog.Devices()
+	index := make(map[string]int, len(devices))
+	for i, d := range devices {
+		index[d.Name] = i
+	}
+
+	// Older recipes may predate normalization, so fold their spellings in
+	for name, count := range counts {
+		canonical := h.catalog.Normalize(name)
+		i, ok := index[canonical]
+		if !ok {
+			i = len(devices)
+			index[canonical] = i
+			devices = append(devices, models.Device{Name: canonical})
+		}
+		devices[i].RecipeCount += count
+	}
+
+	filtered := devices[:0]
+	for _, d := range devices {
+		if category == "" || d.Category == category {
+			filtered = append(filtered, d)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].RecipeCount > filtered[j].RecipeCount
+	})
+
+	respondJSON(w, http.StatusOK, filtered)
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/camwick/sdr-backend/internal/services/database"
+	"github.com/camwick/sdr-backend/internal/services/export"
+	"github.com/camwick/sdr-backend/internal/services/source"
+)
+
+// maxExportRecipes bounds how many recipes go into one bulk export
+const maxExportRecipes = 500
+
+// ExportRecipe returns the recipe as a Markdown (?format=md, the default)
+// or printable HTML (?format=html) cheat sheet
+func (h *Handler) ExportRecipe(w http.ResponseWriter, r *http.Request) {
+	format, err := formatFromQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tutorial, ok := h.loadRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	file, err := export.Sheet(tutorial, format)
+	if !h.exportOK(w, tutorial.ID, err) {
+		return
+	}
+
+	respondFile(w, file)
+}
+
+// ExportRecipes returns a zip of cheat sheets for every recipe by
+// ?creator= (a handle) or of ?sound_type=, approved only unless ?status= is
+// given. ?platform= keeps creators with the same handle on different
+// platforms apart. Accepts ?format= like ExportRecipe.
+func (h *Handler) ExportRecipes(w http.ResponseWriter, r *http.Request) {
+	format, err := formatFromQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := statusFromQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	creator := strings.TrimPrefix(strings.TrimSpace(query.Get("creator")), "@")
+	soundType := strings.ToLower(strings.TrimSpace(query.Get("sound_type")))
+	if creator == "" && soundType == "" {
+		respondError(w, http.StatusBadRequest, "creator or sound_type is required")
+		return
+	}
+
+	platform := strings.ToLower(strings.TrimSpace(query.Get("platform")))
+	if platform != "" && platform != source.Upload && !slices.Contains(h.sources.Platforms(), platform) {
+		respondError(w, http.StatusBadRequest, "Unknown platform")
+		return
+	}
+
+	tutorials, err := h.db.ListTutorials(r.Context(), database.ListOptions{
+		Status:        status,
+		Platform:      platform,
+		CreatorHandle: creator,
+		SoundType:     soundType,
+		Limit:         maxExportRecipes,
+	})
+	if err != nil {
+		log.Printf("Failed to list tutorials for export: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to load recipes")
+		return
+	}
+	if len(tutorials) == 0 {
+		respondError(w, http.StatusNotFound, "No recipes found")
+		return
+	}
+
+	name := strings.TrimSpace(creator + " " + soundType)
+	file, err := export.SheetZip(name, tutorials, format)
+	if err != nil {
+		log.Printf("Failed to export %d tutorials: %v", len(tutorials), err)
+		respondError(w, http.StatusInternalServerError, "Failed to export recipes")
+		return
+	}
+
+	respondFile(w, file)
+}
+
+// ExportAbleton returns the recipe as a Live device rack (.adg)
+func (h *Handler) ExportAbleton(w http.ResponseWriter, r *http.Request) {
+	tutorial, ok := h.loadRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	file, _, err := export.Ableton(tutorial)
+	if !h.exportOK(w, tutorial.ID, err) {
+		return
+	}
+
+	respondFile(w, file)
+}
+
+// ExportAbletonReport returns which devices and parameters the recipe's
+// .adg export includes and which were left at their defaults
+func (h *Handler) ExportAbletonReport(w http.ResponseWriter, r *http.Request) {
+	tutorial, ok := h.loadRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	_, report, err := export.Ableton(tutorial)
+	if !h.exportOK(w, tutorial.ID, err) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// ExportVital returns the recipe as a Vital preset (.vital)
+func (h *Handler) ExportVital(w http.ResponseWriter, r *http.Request) {
+	tutorial, ok := h.loadRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	file, _, err := export.Vital(tutorial)
+	if !h.exportOK(w, tutorial.ID, err) {
+		return
+	}
+
+	respondFile(w, file)
+}
+
+// ExportVitalReport returns which parameters the recipe's .vital export
+// sets and which instructions couldn't be mapped
+func (h *Handler) ExportVitalReport(w http.ResponseWriter, r *http.Request) {
+	tutorial, ok := h.loadRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	_, report, err := export.Vital(tutorial)
+	if !h.exportOK(w, tutorial.ID, err) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// exportOK writes the error response for a failed export and returns false
+func (h *Handler) exportOK(w http.ResponseWriter, id string, err error) bool {
+	if errors.Is(err, export.ErrNothingToExport) {
+		respondError(w, http.StatusUnprocessableEntity, "Recipe has no devices this format can represent")
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to export tutorial %s: %v", id, err)
+		respondError(w, http.StatusInternalServerError, "Failed to export recipe")
+		return false
+	}
+	return true
+}
+
+// formatFromQuery reads the cheat sheet ?format=, defaulting to Markdown
+func formatFromQuery(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "":
+		return export.FormatMarkdown, nil
+	case export.FormatMarkdown, export.FormatHTML:
+		return format, nil
+	default:
+		return "", errors.New("format must be md or html")
+	}
+}
+
+func respondFile(w http.ResponseWriter, file *export.File) {
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Data)
+}
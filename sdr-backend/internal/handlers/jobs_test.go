@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/jobs"
+)
+
+var eventIDPattern = regexp.MustCompile(`(?m)^id: (\d+)$`)
+
+// eventsRequest is a GET for the job's event stream, routed as chi would
+func eventsRequest(id, lastEventID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/jobs/"+id+"/events", nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// streamedIDs lists the IDs of the events written to the stream
+func streamedIDs(body string) []string {
+	var ids []string
+	for _, match := range eventIDPattern.FindAllStringSubmatch(body, -1) {
+		ids = append(ids, match[1])
+	}
+	return ids
+}
+
+func TestJobEvents(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	jobSvc := jobs.NewService(1, 1, func(ctx context.Context, job models.Job, tracker *jobs.Tracker) (*jobs.Result, error) {
+		tracker.Start(models.JobStageExtracting, "Extracting audio...")
+		tracker.Complete(models.JobStageExtracting, "Extracted", nil)
+		close(started)
+		<-release
+		tracker.Start(models.JobStageTranscribing, "Transcribing audio...")
+		return &jobs.Result{TutorialID: "tutorial-1"}, nil
+	})
+	h := &Handler{jobs: jobSvc}
+
+	job, err := jobSvc.Enqueue("https://www.tiktok.com/@producer/video/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// A client reconnecting mid-run gets what it missed, then the rest live
+	live := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.JobEvents(live, eventsRequest(job.ID, "1"))
+	}()
+	close(release)
+	<-done
+
+	if ct := live.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if got, want := streamedIDs(live.Body.String()), []string{"2", "3", "4"}; !slices.Equal(got, want) {
+		t.Errorf("resumed stream has events %v, want %v:\n%s", got, want, live.Body)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string
+	}{
+		{"from the start", "", []string{"1", "2", "3", "4"}},
+		{"resume", "2", []string{"3", "4"}},
+		{"up to date", "4", nil},
+		{"bad header", "abc", []string{"1", "2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.JobEvents(w, eventsRequest(job.ID, tt.lastEventID))
+
+			if got := streamedIDs(w.Body.String()); !slices.Equal(got, tt.want) {
+				t.Errorf("stream has events %v, want %v:\n%s", got, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestJobEventsNotFound(t *testing.T) {
+	h := &Handler{jobs: jobs.NewService(0, 1, nil)}
+
+	w := httptest.NewRecorder()
+	h.JobEvents(w, eventsRequest("missing", ""))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/database"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 100
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ListRecipes returns tutorials for the browse view, approved only unless ?status= is given
+func (h *Handler) ListRecipes(w http.ResponseWriter, r *http.Request) {
+	opts, err := listOptionsFromQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tutorials, err := h.db.ListTutorials(r.Context(), opts)
+	if err != nil {
+		log.Printf("Failed to list tutorials: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to load recipes")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tutorials)
+}
+
+// SearchRecipes returns tutorials matching ?q=, approved only unless ?status= is given
+func (h *Handler) SearchRecipes(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		respondError(w, http.StatusBadRequest, "Search query is required")
+		return
+	}
+
+	opts, err := listOptionsFromQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tutorials, err := h.db.SearchTutorials(r.Context(), query, opts)
+	if err != nil {
+		log.Printf("Failed to search tutorials: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to search recipes")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tutorials)
+}
+
+// GetRecipe returns a single tutorial with its creator and instructions
+func (h *Handler) GetRecipe(w http.ResponseWriter, r *http.Request) {
+	tutorial, ok := h.loadRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tutorial)
+}
+
+// loadRecipe loads the tutorial named by the {id} route parameter, approved
+// only unless ?status= is given. On failure it writes the error response
+// and returns false.
+func (h *Handler) loadRecipe(w http.ResponseWriter, r *http.Request) (*models.Tutorial, bool) {
+	id := chi.URLParam(r, "id")
+	if !uuidPattern.MatchString(id) {
+		respondError(w, http.StatusBadRequest, "Invalid recipe ID")
+		return nil, false
+	}
+
+	status, err := statusFromQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	tutorial, err := h.db.GetTutorialWithInstructions(r.Context(), id)
+	if errors.Is(err, database.ErrNotFound) || (err == nil && status != "" && tutorial.Status != status) {
+		respondError(w, http.StatusNotFound, "Recipe not found")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Failed to get tutorial %s: %v", id, err)
+		respondError(w, http.StatusInternalServerError, "Failed to load recipe")
+		return nil, false
+	}
+
+	return tutorial, true
+}
+
+// listOptionsFromQuery reads ?status=, ?limit= and ?offset= with browse defaults
+func listOptionsFromQuery(r *http.Request) (database.ListOptions, error) {
+	status, err := statusFromQuery(r)
+	if err != nil {
+		return database.ListOptions{}, err
+	}
+
+	opts := database.ListOptions{Status: status, Limit: defaultListLimit}
+	query := r.URL.Query()
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return database.ListOptions{}, errors.New("limit must be between 1 and 100")
+		}
+		opts.Limit = limit
+	}
+
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return database.ListOptions{}, errors.New("offset must be a non-negative integer")
+		}
+		opts.Offset = offset
+	}
+
+	return opts, nil
+}
+
+// statusFromQuery returns the requested tutorial status, defaulting to approved.
+// "all" disables status filtering.
+func statusFromQuery(r *http.Request) (string, error) {
+	switch status := r.URL.Query().Get("status"); status {
+	case "":
+		return "approved", nil
+	case "all":
+		return "", nil
+	case "pending", "approved", "rejected":
+		return status, nil
+	default:
+		return "", errors.New("status must be one of pending, approved, rejected or all")
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/jobs"
+	"github.com/camwick/sdr-backend/internal/services/source"
+)
+
+const (
+	// uploadMemory is how much of a multipart upload is buffered in memory
+	// before the rest spills to a temp file
+	uploadMemory = 8 << 20
+
+	// uploadFormOverhead allows for the form fields and multipart framing
+	// on top of the file itself
+	uploadFormOverhead = 1 << 20
+)
+
+var handlePattern = regexp.MustCompile(`^[\w.-]{1,100}$`)
+
+// TranscribeUpload queues an uploaded video or audio file for the
+// transcription pipeline. The multipart form carries the file as "file",
+// the creator's "creator_handle" and optionally their "creator_name".
+func (h *Handler) TranscribeUpload(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.uploads.MaxBytes()+uploadFormOverhead)
+	if err := r.ParseMultipartForm(uploadMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(w, http.StatusRequestEntityTooLarge, uploadTooLarge(h.uploads))
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Invalid multipart form")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	handle := strings.TrimPrefix(strings.TrimSpace(r.FormValue("creator_handle")), "@")
+	if !handlePattern.MatchString(handle) {
+		respondError(w, http.StatusBadRequest, "creator_handle is required and may only contain letters, numbers, '_', '.' and '-'")
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("creator_name"))
+	if name == "" {
+		name = handle
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "A video or audio file is required")
+		return
+	}
+	defer file.Close()
+
+	upload, err := h.uploads.Save(r.Context(), file, header.Filename)
+	switch {
+	case errors.Is(err, source.ErrTooLarge):
+		respondError(w, http.StatusRequestEntityTooLarge, uploadTooLarge(h.uploads))
+		return
+	case errors.Is(err, source.ErrTooLong):
+		respondError(w, http.StatusBadRequest,
+			fmt.Sprintf("Recording is too long, the limit is %g minutes", h.uploads.MaxDuration().Minutes()))
+		return
+	case errors.Is(err, source.ErrNoAudio):
+		respondError(w, http.StatusUnsupportedMediaType, "File is not a readable video or audio file")
+		return
+	case err != nil:
+		log.Printf("Failed to save upload: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to save upload")
+		return
+	}
+	upload.CreatorHandle = handle
+	upload.CreatorName = name
+
+	job, err := h.jobs.EnqueueUpload(upload)
+	if errors.Is(err, jobs.ErrQueueFull) {
+		h.uploads.Cleanup(upload.FileID)
+		respondError(w, http.StatusServiceUnavailable, "Too many transcriptions in progress, please try again later")
+		return
+	}
+	if err != nil {
+		h.uploads.Cleanup(upload.FileID)
+		log.Printf("Failed to enqueue job: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to queue transcription")
+		return
+	}
+
+	log.Printf("Queued job %s for upload %s (%.0fs) from @%s", job.ID, upload.FileName, upload.Duration, handle)
+
+	w.Header().Set("Location", "/api/jobs/"+job.ID)
+	respondJSON(w, http.StatusAccepted, models.TranscribeResponse{
+		Success: true,
+		Message: "Transcription queued",
+		Job:     job,
+	})
+}
+
+func uploadTooLarge(uploads *source.Uploads) string {
+	return fmt.Sprintf("File is too large, the limit is %d MB", uploads.MaxBytes()>>20)
+}
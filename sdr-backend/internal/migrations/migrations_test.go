@@ -0,0 +1,171 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/migrations"
+	"github.com/camwick/sdr-backend/internal/services/database/sqlite"
+)
+
+func openSQLite(t *testing.T) (*sql.DB, *migrations.Migrator) {
+	t.Helper()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "sdr.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.New(db, migrations.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, migrator
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+func TestSQLiteUpDown(t *testing.T) {
+	ctx := context.Background()
+	db, migrator := openSQLite(t)
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(statuses) {
+		t.Fatalf("applied %d of %d migrations", len(applied), len(statuses))
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("migration %d not recorded as applied", s.Version)
+		}
+	}
+
+	if again, err := migrator.Up(ctx); err != nil || len(again) != 0 {
+		t.Fatalf("second Up = %d migrations, %v, want none", len(again), err)
+	}
+
+	for i := len(statuses); i > 0; i-- {
+		rolledBack, err := migrator.Down(ctx)
+		if err != nil {
+			t.Fatalf("rolling back %d: %v", i, err)
+		}
+		if rolledBack == nil || rolledBack.Version != i {
+			t.Fatalf("Down = %+v, want version %d", rolledBack, i)
+		}
+	}
+	if rolledBack, err := migrator.Down(ctx); err != nil || rolledBack != nil {
+		t.Fatalf("Down with nothing applied = %+v, %v", rolledBack, err)
+	}
+
+	for _, table := range []string{"creators", "tutorials", "instructions", "transcript_segments"} {
+		if tableExists(t, db, table) {
+			t.Errorf("table %s still exists after rolling everything back", table)
+		}
+	}
+
+	if applied, err := migrator.Up(ctx); err != nil || len(applied) != len(statuses) {
+		t.Fatalf("Up after Down = %d migrations, %v", len(applied), err)
+	}
+}
+
+// TestSQLiteKeepsDataThroughPlatformMigrations seeds a database at version 4
+// and checks the tutorials and creators rebuilds keep every row and foreign key
+func TestSQLiteKeepsDataThroughPlatformMigrations(t *testing.T) {
+	ctx := context.Background()
+	db, migrator := openSQLite(t)
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		rolledBack, err := migrator.Down(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rolledBack == nil || rolledBack.Version == 5 {
+			break
+		}
+	}
+
+	seed := []string{
+		`INSERT INTO creators (id, tiktok_handle, display_name, created_at)
+		 VALUES ('c1', 'producer', 'Producer', CURRENT_TIMESTAMP)`,
+		`INSERT INTO tutorials (id, creator_id, tiktok_url, tiktok_video_id, title, sound_type, raw_transcription, created_at, updated_at)
+		 VALUES ('t1', 'c1', 'https://www.tiktok.com/@producer/video/1', '1', 'Reese Bass', 'bass', 'load wavetable', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+		`INSERT INTO instructions (id, tutorial_id, step_number, description, start_time, end_time)
+		 VALUES ('i1', 't1', 1, 'Load Wavetable', 1.5, 4)`,
+		`INSERT INTO transcript_segments (id, tutorial_id, segment_index, start_time, end_time, text)
+		 VALUES ('s1', 't1', 0, 0, 4, 'load wavetable')`,
+	}
+	for _, stmt := range seed {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var platform, handle string
+	if err := db.QueryRow(`SELECT platform, handle FROM creators WHERE id = 'c1'`).Scan(&platform, &handle); err != nil {
+		t.Fatal(err)
+	}
+	if platform != "tiktok" || handle != "producer" {
+		t.Errorf("creator = %s/%s, want tiktok/producer", platform, handle)
+	}
+
+	var videoID, sourceURL string
+	if err := db.QueryRow(`SELECT platform, video_id, source_url FROM tutorials WHERE id = 't1'`).Scan(&platform, &videoID, &sourceURL); err != nil {
+		t.Fatal(err)
+	}
+	if platform != "tiktok" || videoID != "1" || sourceURL != "https://www.tiktok.com/@producer/video/1" {
+		t.Errorf("tutorial = %s %s %s", platform, videoID, sourceURL)
+	}
+
+	var startTime float64
+	if err := db.QueryRow(`SELECT start_time FROM instructions WHERE id = 'i1'`).Scan(&startTime); err != nil {
+		t.Fatal(err)
+	}
+	if startTime != 1.5 {
+		t.Errorf("start_time = %g, want 1.5", startTime)
+	}
+
+	// The same handle on another platform is a different creator
+	if _, err := db.ExecContext(ctx, `INSERT INTO creators (id, platform, handle, display_name, created_at)
+		VALUES ('c2', 'youtube', 'producer', 'Producer', CURRENT_TIMESTAMP)`); err != nil {
+		t.Errorf("creating the same handle on youtube: %v", err)
+	}
+
+	// The foreign keys point at the rebuilt tables, so deletes still cascade
+	if _, err := db.ExecContext(ctx, `DELETE FROM creators WHERE id = 'c1'`); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"tutorials", "instructions", "transcript_segments"} {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n != 0 {
+			t.Errorf("%s has %d rows after deleting the creator, want 0", table, n)
+		}
+	}
+}
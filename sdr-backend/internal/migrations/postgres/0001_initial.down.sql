@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS instructions;
+DROP TABLE IF EXISTS tutorials;
+DROP TABLE IF EXISTS creators;
+DROP FUNCTION IF EXISTS update_updated_at();
@@ -0,0 +1,59 @@
+-- Creators, tutorials and instructions as defined in schema.sql. Statements
+-- are idempotent so databases bootstrapped from schema.sql can adopt
+-- migrations. Supabase row level security policies stay in schema.sql.
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS creators (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tiktok_handle VARCHAR(255) UNIQUE NOT NULL,
+    display_name VARCHAR(255) NOT NULL,
+    avatar_url TEXT,
+    is_claimed BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tutorials (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    creator_id UUID NOT NULL REFERENCES creators(id) ON DELETE CASCADE,
+    tiktok_url TEXT NOT NULL,
+    tiktok_video_id VARCHAR(255) UNIQUE NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    sound_type VARCHAR(100) NOT NULL,
+    raw_transcription TEXT NOT NULL,
+    status VARCHAR(50) DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS instructions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tutorial_id UUID NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    step_number INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    ableton_device VARCHAR(255),
+    parameters JSONB DEFAULT '{}',
+    notes TEXT,
+    screenshot_url TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_tutorials_creator_id ON tutorials(creator_id);
+CREATE INDEX IF NOT EXISTS idx_tutorials_status ON tutorials(status);
+CREATE INDEX IF NOT EXISTS idx_tutorials_sound_type ON tutorials(sound_type);
+CREATE INDEX IF NOT EXISTS idx_tutorials_video_id ON tutorials(tiktok_video_id);
+CREATE INDEX IF NOT EXISTS idx_instructions_tutorial_id ON instructions(tutorial_id);
+CREATE INDEX IF NOT EXISTS idx_creators_handle ON creators(tiktok_handle);
+
+CREATE OR REPLACE FUNCTION update_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tutorials_updated_at ON tutorials;
+CREATE TRIGGER tutorials_updated_at
+    BEFORE UPDATE ON tutorials
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
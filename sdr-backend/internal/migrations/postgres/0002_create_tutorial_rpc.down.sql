@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS tutorial_id_for_video(TEXT);
+DROP FUNCTION IF EXISTS create_tutorial_with_instructions(JSONB, JSONB);
@@ -0,0 +1,57 @@
+-- Saves a tutorial and all of its instructions in one transaction. Called
+-- through PostgREST as POST /rest/v1/rpc/create_tutorial_with_instructions.
+-- SECURITY INVOKER keeps row level security in force, so callers can only
+-- insert what the insert policies already allow. The new tutorial is
+-- pending, which the public select policy hides, so it is inserted without
+-- RETURNING and the saved row is built up here instead.
+
+CREATE OR REPLACE FUNCTION create_tutorial_with_instructions(p_tutorial JSONB, p_instructions JSONB)
+RETURNS tutorials
+LANGUAGE plpgsql
+SECURITY INVOKER
+SET search_path = public
+AS $$
+DECLARE
+    saved tutorials;
+BEGIN
+    saved.id := gen_random_uuid();
+    saved.creator_id := (p_tutorial->>'creator_id')::UUID;
+    saved.tiktok_url := p_tutorial->>'tiktok_url';
+    saved.tiktok_video_id := p_tutorial->>'tiktok_video_id';
+    saved.title := p_tutorial->>'title';
+    saved.sound_type := p_tutorial->>'sound_type';
+    saved.raw_transcription := p_tutorial->>'raw_transcription';
+    saved.status := 'pending';
+    saved.created_at := NOW();
+    saved.updated_at := saved.created_at;
+
+    INSERT INTO tutorials (id, creator_id, tiktok_url, tiktok_video_id, title, sound_type, raw_transcription, status, created_at, updated_at)
+    VALUES (saved.id, saved.creator_id, saved.tiktok_url, saved.tiktok_video_id, saved.title, saved.sound_type,
+            saved.raw_transcription, saved.status, saved.created_at, saved.updated_at);
+
+    INSERT INTO instructions (tutorial_id, step_number, description, ableton_device, parameters, notes)
+    SELECT saved.id, i.step_number, i.description, i.ableton_device, COALESCE(i.parameters, '{}'), i.notes
+    FROM jsonb_to_recordset(COALESCE(p_instructions, '[]'::JSONB))
+        AS i(step_number INTEGER, description TEXT, ableton_device VARCHAR(255), parameters JSONB, notes TEXT);
+
+    RETURN saved;
+END;
+$$;
+
+GRANT EXECUTE ON FUNCTION create_tutorial_with_instructions(JSONB, JSONB) TO PUBLIC;
+
+-- The ID of the tutorial saved from a video, whatever its status. Pending
+-- tutorials are hidden from the anon key, so this is how a save that hits
+-- the unique constraint finds the tutorial it collided with. SECURITY
+-- DEFINER so it can see them; it only ever returns the ID.
+CREATE OR REPLACE FUNCTION tutorial_id_for_video(p_video_id TEXT)
+RETURNS UUID
+LANGUAGE sql
+STABLE
+SECURITY DEFINER
+SET search_path = public
+AS $$
+    SELECT id FROM tutorials WHERE tiktok_video_id = p_video_id;
+$$;
+
+GRANT EXECUTE ON FUNCTION tutorial_id_for_video(TEXT) TO PUBLIC;
@@ -0,0 +1,43 @@
+DROP FUNCTION IF EXISTS create_tutorial_with_instructions(JSONB, JSONB, JSONB);
+ALTER TABLE instructions DROP COLUMN IF EXISTS end_time;
+ALTER TABLE instructions DROP COLUMN IF EXISTS start_time;
+DROP POLICY IF EXISTS "Anon can insert transcript segments" ON transcript_segments;
+DROP POLICY IF EXISTS "Service role full access to transcript segments" ON transcript_segments;
+DROP POLICY IF EXISTS "Public can view transcript segments" ON transcript_segments;
+DROP TABLE IF EXISTS transcript_segments;
+
+-- Restore the two-argument RPC from 0002
+CREATE OR REPLACE FUNCTION create_tutorial_with_instructions(p_tutorial JSONB, p_instructions JSONB)
+RETURNS tutorials
+LANGUAGE plpgsql
+SECURITY INVOKER
+SET search_path = public
+AS $$
+DECLARE
+    saved tutorials;
+BEGIN
+    saved.id := gen_random_uuid();
+    saved.creator_id := (p_tutorial->>'creator_id')::UUID;
+    saved.tiktok_url := p_tutorial->>'tiktok_url';
+    saved.tiktok_video_id := p_tutorial->>'tiktok_video_id';
+    saved.title := p_tutorial->>'title';
+    saved.sound_type := p_tutorial->>'sound_type';
+    saved.raw_transcription := p_tutorial->>'raw_transcription';
+    saved.status := 'pending';
+    saved.created_at := NOW();
+    saved.updated_at := saved.created_at;
+
+    INSERT INTO tutorials (id, creator_id, tiktok_url, tiktok_video_id, title, sound_type, raw_transcription, status, created_at, updated_at)
+    VALUES (saved.id, saved.creator_id, saved.tiktok_url, saved.tiktok_video_id, saved.title, saved.sound_type,
+            saved.raw_transcription, saved.status, saved.created_at, saved.updated_at);
+
+    INSERT INTO instructions (tutorial_id, step_number, description, ableton_device, parameters, notes)
+    SELECT saved.id, i.step_number, i.description, i.ableton_device, COALESCE(i.parameters, '{}'), i.notes
+    FROM jsonb_to_recordset(COALESCE(p_instructions, '[]'::JSONB))
+        AS i(step_number INTEGER, description TEXT, ableton_device VARCHAR(255), parameters JSONB, notes TEXT);
+
+    RETURN saved;
+END;
+$$;
+
+GRANT EXECUTE ON FUNCTION create_tutorial_with_instructions(JSONB, JSONB) TO PUBLIC;
@@ -0,0 +1,91 @@
+-- Timestamped transcript segments, and where in the video each instruction
+-- is explained. The RPC gains a p_segments argument so the tutorial, its
+-- instructions and its segments are still saved in one transaction.
+
+CREATE TABLE IF NOT EXISTS transcript_segments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tutorial_id UUID NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    segment_index INTEGER NOT NULL,
+    start_time DOUBLE PRECISION NOT NULL,
+    end_time DOUBLE PRECISION NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (tutorial_id, segment_index)
+);
+
+-- Segments are readable only with an approved tutorial, like instructions.
+-- Policies are dropped first so databases bootstrapped from schema.sql can
+-- adopt this migration.
+ALTER TABLE transcript_segments ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Public can view transcript segments" ON transcript_segments;
+CREATE POLICY "Public can view transcript segments" ON transcript_segments
+    FOR SELECT USING (
+        EXISTS (
+            SELECT 1 FROM tutorials
+            WHERE tutorials.id = transcript_segments.tutorial_id
+            AND tutorials.status = 'approved'
+        )
+    );
+
+-- The RPC runs with the caller's privileges, so segments need an insert
+-- policy like instructions have
+DROP POLICY IF EXISTS "Anon can insert transcript segments" ON transcript_segments;
+CREATE POLICY "Anon can insert transcript segments" ON transcript_segments
+    FOR INSERT WITH CHECK (true);
+
+-- auth.role() only exists on Supabase
+DO $$
+BEGIN
+    IF to_regprocedure('auth.role()') IS NOT NULL THEN
+        DROP POLICY IF EXISTS "Service role full access to transcript segments" ON transcript_segments;
+        CREATE POLICY "Service role full access to transcript segments" ON transcript_segments
+            FOR ALL USING (auth.role() = 'service_role');
+    END IF;
+END;
+$$;
+
+ALTER TABLE instructions ADD COLUMN IF NOT EXISTS start_time DOUBLE PRECISION;
+ALTER TABLE instructions ADD COLUMN IF NOT EXISTS end_time DOUBLE PRECISION;
+
+DROP FUNCTION IF EXISTS create_tutorial_with_instructions(JSONB, JSONB);
+
+CREATE OR REPLACE FUNCTION create_tutorial_with_instructions(p_tutorial JSONB, p_instructions JSONB, p_segments JSONB DEFAULT '[]')
+RETURNS tutorials
+LANGUAGE plpgsql
+SECURITY INVOKER
+SET search_path = public
+AS $$
+DECLARE
+    saved tutorials;
+BEGIN
+    saved.id := gen_random_uuid();
+    saved.creator_id := (p_tutorial->>'creator_id')::UUID;
+    saved.tiktok_url := p_tutorial->>'tiktok_url';
+    saved.tiktok_video_id := p_tutorial->>'tiktok_video_id';
+    saved.title := p_tutorial->>'title';
+    saved.sound_type := p_tutorial->>'sound_type';
+    saved.raw_transcription := p_tutorial->>'raw_transcription';
+    saved.status := 'pending';
+    saved.created_at := NOW();
+    saved.updated_at := saved.created_at;
+
+    INSERT INTO tutorials (id, creator_id, tiktok_url, tiktok_video_id, title, sound_type, raw_transcription, status, created_at, updated_at)
+    VALUES (saved.id, saved.creator_id, saved.tiktok_url, saved.tiktok_video_id, saved.title, saved.sound_type,
+            saved.raw_transcription, saved.status, saved.created_at, saved.updated_at);
+
+    INSERT INTO instructions (tutorial_id, step_number, description, ableton_device, parameters, notes, start_time, end_time)
+    SELECT saved.id, i.step_number, i.description, i.ableton_device, COALESCE(i.parameters, '{}'), i.notes, i.start_time, i.end_time
+    FROM jsonb_to_recordset(COALESCE(p_instructions, '[]'::JSONB))
+        AS i(step_number INTEGER, description TEXT, ableton_device VARCHAR(255), parameters JSONB, notes TEXT,
+             start_time DOUBLE PRECISION, end_time DOUBLE PRECISION);
+
+    INSERT INTO transcript_segments (tutorial_id, segment_index, start_time, end_time, text)
+    SELECT saved.id, s.segment_index, s.start_time, s.end_time, s.text
+    FROM jsonb_to_recordset(COALESCE(p_segments, '[]'::JSONB))
+        AS s(segment_index INTEGER, start_time DOUBLE PRECISION, end_time DOUBLE PRECISION, text TEXT);
+
+    RETURN saved;
+END;
+$$;
+
+GRANT EXECUTE ON FUNCTION create_tutorial_with_instructions(JSONB, JSONB, JSONB) TO PUBLIC;
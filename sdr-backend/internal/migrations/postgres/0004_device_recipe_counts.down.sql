@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS device_recipe_counts(TEXT);
@@ -0,0 +1,21 @@
+-- Tutorials per device, for GET /api/devices. Called through PostgREST as
+-- POST /rest/v1/rpc/device_recipe_counts. An empty p_status counts every
+-- tutorial the caller can see; SECURITY INVOKER keeps row level security
+-- in force, so the anon key only counts approved tutorials.
+
+CREATE OR REPLACE FUNCTION device_recipe_counts(p_status TEXT DEFAULT '')
+RETURNS TABLE (ableton_device VARCHAR(255), recipe_count BIGINT)
+LANGUAGE sql
+STABLE
+SECURITY INVOKER
+SET search_path = public
+AS $$
+    SELECT i.ableton_device, COUNT(DISTINCT i.tutorial_id)
+    FROM instructions i
+    JOIN tutorials t ON t.id = i.tutorial_id
+    WHERE (p_status = '' OR t.status = p_status)
+    AND COALESCE(i.ableton_device, '') <> ''
+    GROUP BY i.ableton_device;
+$$;
+
+GRANT EXECUTE ON FUNCTION device_recipe_counts(TEXT) TO PUBLIC;
@@ -0,0 +1,65 @@
+-- Tutorials from platforms other than TikTok can't be represented before
+-- this migration and are deleted.
+
+DELETE FROM tutorials WHERE platform <> 'tiktok';
+
+ALTER TABLE tutorials DROP CONSTRAINT IF EXISTS tutorials_platform_video_id_key;
+ALTER TABLE tutorials DROP COLUMN platform;
+ALTER TABLE tutorials RENAME COLUMN video_id TO tiktok_video_id;
+ALTER TABLE tutorials RENAME COLUMN source_url TO tiktok_url;
+ALTER TABLE tutorials ADD CONSTRAINT tutorials_tiktok_video_id_key UNIQUE (tiktok_video_id);
+CREATE INDEX IF NOT EXISTS idx_tutorials_video_id ON tutorials(tiktok_video_id);
+
+-- Restore the RPCs from 0002 and 0003
+CREATE OR REPLACE FUNCTION create_tutorial_with_instructions(p_tutorial JSONB, p_instructions JSONB, p_segments JSONB DEFAULT '[]')
+RETURNS tutorials
+LANGUAGE plpgsql
+SECURITY INVOKER
+SET search_path = public
+AS $$
+DECLARE
+    saved tutorials;
+BEGIN
+    saved.id := gen_random_uuid();
+    saved.creator_id := (p_tutorial->>'creator_id')::UUID;
+    saved.tiktok_url := p_tutorial->>'tiktok_url';
+    saved.tiktok_video_id := p_tutorial->>'tiktok_video_id';
+    saved.title := p_tutorial->>'title';
+    saved.sound_type := p_tutorial->>'sound_type';
+    saved.raw_transcription := p_tutorial->>'raw_transcription';
+    saved.status := 'pending';
+    saved.created_at := NOW();
+    saved.updated_at := saved.created_at;
+
+    INSERT INTO tutorials (id, creator_id, tiktok_url, tiktok_video_id, title, sound_type, raw_transcription, status, created_at, updated_at)
+    VALUES (saved.id, saved.creator_id, saved.tiktok_url, saved.tiktok_video_id, saved.title, saved.sound_type,
+            saved.raw_transcription, saved.status, saved.created_at, saved.updated_at);
+
+    INSERT INTO instructions (tutorial_id, step_number, description, ableton_device, parameters, notes, start_time, end_time)
+    SELECT saved.id, i.step_number, i.description, i.ableton_device, COALESCE(i.parameters, '{}'), i.notes, i.start_time, i.end_time
+    FROM jsonb_to_recordset(COALESCE(p_instructions, '[]'::JSONB))
+        AS i(step_number INTEGER, description TEXT, ableton_device VARCHAR(255), parameters JSONB, notes TEXT,
+             start_time DOUBLE PRECISION, end_time DOUBLE PRECISION);
+
+    INSERT INTO transcript_segments (tutorial_id, segment_index, start_time, end_time, text)
+    SELECT saved.id, s.segment_index, s.start_time, s.end_time, s.text
+    FROM jsonb_to_recordset(COALESCE(p_segments, '[]'::JSONB))
+        AS s(segment_index INTEGER, start_time DOUBLE PRECISION, end_time DOUBLE PRECISION, text TEXT);
+
+    RETURN saved;
+END;
+$$;
+
+DROP FUNCTION IF EXISTS tutorial_id_for_video(TEXT, TEXT);
+
+CREATE OR REPLACE FUNCTION tutorial_id_for_video(p_video_id TEXT)
+RETURNS UUID
+LANGUAGE sql
+STABLE
+SECURITY DEFINER
+SET search_path = public
+AS $$
+    SELECT id FROM tutorials WHERE tiktok_video_id = p_video_id;
+$$;
+
+GRANT EXECUTE ON FUNCTION tutorial_id_for_video(TEXT) TO PUBLIC;
@@ -0,0 +1,10 @@
+-- Creators from platforms other than TikTok can't be represented before
+-- this migration and are deleted, along with their tutorials.
+
+DELETE FROM creators WHERE platform <> 'tiktok';
+
+ALTER TABLE creators DROP CONSTRAINT IF EXISTS creators_platform_handle_key;
+ALTER TABLE creators DROP COLUMN platform;
+ALTER TABLE creators RENAME COLUMN handle TO tiktok_handle;
+ALTER TABLE creators ADD CONSTRAINT creators_tiktok_handle_key UNIQUE (tiktok_handle);
+CREATE INDEX IF NOT EXISTS idx_creators_handle ON creators(tiktok_handle);
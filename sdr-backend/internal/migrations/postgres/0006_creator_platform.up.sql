@@ -0,0 +1,20 @@
+-- Creators are keyed on platform and handle, so @foo on YouTube isn't
+-- merged into @foo on TikTok. Existing creators whose tutorials all come
+-- from one platform take that platform; the rest stay on TikTok.
+
+ALTER TABLE creators RENAME COLUMN tiktok_handle TO handle;
+ALTER TABLE creators ADD COLUMN platform VARCHAR(50) NOT NULL DEFAULT 'tiktok';
+ALTER TABLE creators ALTER COLUMN platform DROP DEFAULT;
+
+UPDATE creators c SET platform = t.platform
+FROM (
+    SELECT creator_id, MIN(platform) AS platform
+    FROM tutorials
+    GROUP BY creator_id
+    HAVING COUNT(DISTINCT platform) = 1
+) t
+WHERE t.creator_id = c.id;
+
+ALTER TABLE creators DROP CONSTRAINT IF EXISTS creators_tiktok_handle_key;
+ALTER TABLE creators ADD CONSTRAINT creators_platform_handle_key UNIQUE (platform, handle);
+DROP INDEX IF EXISTS idx_creators_handle;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS tutorials_updated_at;
+DROP TABLE IF EXISTS instructions;
+DROP TABLE IF EXISTS tutorials;
+DROP TABLE IF EXISTS creators;
@@ -0,0 +1,49 @@
+-- SQLite mirror of the creators, tutorials and instructions tables in
+-- schema.sql. UUIDs and timestamps are stored as TEXT and parameters as a
+-- JSON string.
+
+CREATE TABLE IF NOT EXISTS creators (
+    id TEXT PRIMARY KEY,
+    tiktok_handle TEXT UNIQUE NOT NULL,
+    display_name TEXT NOT NULL,
+    avatar_url TEXT,
+    is_claimed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tutorials (
+    id TEXT PRIMARY KEY,
+    creator_id TEXT NOT NULL REFERENCES creators(id) ON DELETE CASCADE,
+    tiktok_url TEXT NOT NULL,
+    tiktok_video_id TEXT UNIQUE NOT NULL,
+    title TEXT NOT NULL,
+    sound_type TEXT NOT NULL,
+    raw_transcription TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS instructions (
+    id TEXT PRIMARY KEY,
+    tutorial_id TEXT NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    step_number INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    ableton_device TEXT,
+    parameters TEXT NOT NULL DEFAULT '{}' CHECK (json_valid(parameters)),
+    notes TEXT,
+    screenshot_url TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_tutorials_creator_id ON tutorials(creator_id);
+CREATE INDEX IF NOT EXISTS idx_tutorials_status ON tutorials(status);
+CREATE INDEX IF NOT EXISTS idx_tutorials_sound_type ON tutorials(sound_type);
+CREATE INDEX IF NOT EXISTS idx_instructions_tutorial_id ON instructions(tutorial_id);
+
+CREATE TRIGGER IF NOT EXISTS tutorials_updated_at
+    AFTER UPDATE ON tutorials
+    FOR EACH ROW
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE tutorials SET updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now') WHERE id = NEW.id;
+END;
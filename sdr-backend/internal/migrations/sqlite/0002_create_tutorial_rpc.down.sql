@@ -0,0 +1 @@
+-- Postgres only, see 0002_create_tutorial_rpc.up.sql.
@@ -0,0 +1,2 @@
+-- Postgres only: the SQLite backend saves tutorials in a local transaction.
+-- Kept so migration versions line up across dialects.
@@ -0,0 +1,3 @@
+ALTER TABLE instructions DROP COLUMN end_time;
+ALTER TABLE instructions DROP COLUMN start_time;
+DROP TABLE IF EXISTS transcript_segments;
@@ -0,0 +1,15 @@
+-- Timestamped transcript segments, and where in the video each instruction
+-- is explained. Times are seconds into the video.
+
+CREATE TABLE IF NOT EXISTS transcript_segments (
+    id TEXT PRIMARY KEY,
+    tutorial_id TEXT NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    segment_index INTEGER NOT NULL,
+    start_time REAL NOT NULL,
+    end_time REAL NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (tutorial_id, segment_index)
+);
+
+ALTER TABLE instructions ADD COLUMN start_time REAL;
+ALTER TABLE instructions ADD COLUMN end_time REAL;
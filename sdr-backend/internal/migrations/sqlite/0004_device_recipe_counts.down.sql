@@ -0,0 +1 @@
+-- Postgres only, see 0004_device_recipe_counts.up.sql.
@@ -0,0 +1,2 @@
+-- Postgres only: the SQLite backend counts devices with a plain query.
+-- Kept so migration versions line up across dialects.
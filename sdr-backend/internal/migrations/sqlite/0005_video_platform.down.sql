@@ -0,0 +1,71 @@
+-- Reverses the rebuild in 0005_video_platform.up.sql. Tutorials from
+-- platforms other than TikTok are deleted, along with their instructions
+-- and segments.
+
+DELETE FROM tutorials WHERE platform <> 'tiktok';
+
+DROP TRIGGER IF EXISTS tutorials_updated_at;
+ALTER TABLE tutorials RENAME TO tutorials_old;
+
+CREATE TABLE tutorials (
+    id TEXT PRIMARY KEY,
+    creator_id TEXT NOT NULL REFERENCES creators(id) ON DELETE CASCADE,
+    tiktok_url TEXT NOT NULL,
+    tiktok_video_id TEXT UNIQUE NOT NULL,
+    title TEXT NOT NULL,
+    sound_type TEXT NOT NULL,
+    raw_transcription TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+INSERT INTO tutorials (id, creator_id, tiktok_url, tiktok_video_id, title, sound_type, raw_transcription, status, created_at, updated_at)
+SELECT id, creator_id, source_url, video_id, title, sound_type, raw_transcription, status, created_at, updated_at
+FROM tutorials_old;
+
+CREATE TABLE instructions_new (
+    id TEXT PRIMARY KEY,
+    tutorial_id TEXT NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    step_number INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    ableton_device TEXT,
+    parameters TEXT NOT NULL DEFAULT '{}' CHECK (json_valid(parameters)),
+    notes TEXT,
+    screenshot_url TEXT,
+    start_time REAL,
+    end_time REAL
+);
+
+INSERT INTO instructions_new SELECT id, tutorial_id, step_number, description, ableton_device, parameters, notes, screenshot_url, start_time, end_time FROM instructions;
+DROP TABLE instructions;
+ALTER TABLE instructions_new RENAME TO instructions;
+
+CREATE TABLE transcript_segments_new (
+    id TEXT PRIMARY KEY,
+    tutorial_id TEXT NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    segment_index INTEGER NOT NULL,
+    start_time REAL NOT NULL,
+    end_time REAL NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (tutorial_id, segment_index)
+);
+
+INSERT INTO transcript_segments_new SELECT id, tutorial_id, segment_index, start_time, end_time, text FROM transcript_segments;
+DROP TABLE transcript_segments;
+ALTER TABLE transcript_segments_new RENAME TO transcript_segments;
+
+DROP TABLE tutorials_old;
+
+CREATE INDEX IF NOT EXISTS idx_tutorials_creator_id ON tutorials(creator_id);
+CREATE INDEX IF NOT EXISTS idx_tutorials_status ON tutorials(status);
+CREATE INDEX IF NOT EXISTS idx_tutorials_sound_type ON tutorials(sound_type);
+CREATE INDEX IF NOT EXISTS idx_instructions_tutorial_id ON instructions(tutorial_id);
+
+CREATE TRIGGER IF NOT EXISTS tutorials_updated_at
+    AFTER UPDATE ON tutorials
+    FOR EACH ROW
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE tutorials SET updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now') WHERE id = NEW.id;
+END;
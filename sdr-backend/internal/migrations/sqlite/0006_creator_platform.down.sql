@@ -0,0 +1,86 @@
+-- Reverses the rebuild in 0006_creator_platform.up.sql. Creators from
+-- platforms other than TikTok are deleted, along with their tutorials,
+-- instructions and segments.
+
+DELETE FROM creators WHERE platform <> 'tiktok';
+
+DROP TRIGGER IF EXISTS tutorials_updated_at;
+ALTER TABLE creators RENAME TO creators_old;
+ALTER TABLE tutorials RENAME TO tutorials_old;
+
+CREATE TABLE creators (
+    id TEXT PRIMARY KEY,
+    tiktok_handle TEXT UNIQUE NOT NULL,
+    display_name TEXT NOT NULL,
+    avatar_url TEXT,
+    is_claimed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL
+);
+
+INSERT INTO creators (id, tiktok_handle, display_name, avatar_url, is_claimed, created_at)
+SELECT id, handle, display_name, avatar_url, is_claimed, created_at
+FROM creators_old;
+
+CREATE TABLE tutorials (
+    id TEXT PRIMARY KEY,
+    creator_id TEXT NOT NULL REFERENCES creators(id) ON DELETE CASCADE,
+    platform TEXT NOT NULL,
+    source_url TEXT NOT NULL,
+    video_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    sound_type TEXT NOT NULL,
+    raw_transcription TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (platform, video_id)
+);
+
+INSERT INTO tutorials SELECT id, creator_id, platform, source_url, video_id, title, sound_type, raw_transcription, status, created_at, updated_at FROM tutorials_old;
+
+CREATE TABLE instructions_new (
+    id TEXT PRIMARY KEY,
+    tutorial_id TEXT NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    step_number INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    ableton_device TEXT,
+    parameters TEXT NOT NULL DEFAULT '{}' CHECK (json_valid(parameters)),
+    notes TEXT,
+    screenshot_url TEXT,
+    start_time REAL,
+    end_time REAL
+);
+
+INSERT INTO instructions_new SELECT id, tutorial_id, step_number, description, ableton_device, parameters, notes, screenshot_url, start_time, end_time FROM instructions;
+DROP TABLE instructions;
+ALTER TABLE instructions_new RENAME TO instructions;
+
+CREATE TABLE transcript_segments_new (
+    id TEXT PRIMARY KEY,
+    tutorial_id TEXT NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    segment_index INTEGER NOT NULL,
+    start_time REAL NOT NULL,
+    end_time REAL NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (tutorial_id, segment_index)
+);
+
+INSERT INTO transcript_segments_new SELECT id, tutorial_id, segment_index, start_time, end_time, text FROM transcript_segments;
+DROP TABLE transcript_segments;
+ALTER TABLE transcript_segments_new RENAME TO transcript_segments;
+
+DROP TABLE tutorials_old;
+DROP TABLE creators_old;
+
+CREATE INDEX IF NOT EXISTS idx_tutorials_creator_id ON tutorials(creator_id);
+CREATE INDEX IF NOT EXISTS idx_tutorials_status ON tutorials(status);
+CREATE INDEX IF NOT EXISTS idx_tutorials_sound_type ON tutorials(sound_type);
+CREATE INDEX IF NOT EXISTS idx_instructions_tutorial_id ON instructions(tutorial_id);
+
+CREATE TRIGGER IF NOT EXISTS tutorials_updated_at
+    AFTER UPDATE ON tutorials
+    FOR EACH ROW
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE tutorials SET updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now') WHERE id = NEW.id;
+END;
@@ -0,0 +1,97 @@
+-- Creators are keyed on platform and handle, so @foo on YouTube isn't
+-- merged into @foo on TikTok. Existing creators whose tutorials all come
+-- from one platform take that platform; the rest stay on TikTok.
+--
+-- SQLite can't drop the UNIQUE constraint on the handle, so creators is
+-- rebuilt. As in 0005, renaming a table repoints the foreign keys that
+-- reference it, so tutorials, instructions and transcript_segments are
+-- rebuilt as well and the old tables are dropped last.
+
+DROP TRIGGER IF EXISTS tutorials_updated_at;
+ALTER TABLE creators RENAME TO creators_old;
+ALTER TABLE tutorials RENAME TO tutorials_old;
+
+CREATE TABLE creators (
+    id TEXT PRIMARY KEY,
+    platform TEXT NOT NULL,
+    handle TEXT NOT NULL,
+    display_name TEXT NOT NULL,
+    avatar_url TEXT,
+    is_claimed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (platform, handle)
+);
+
+INSERT INTO creators (id, platform, handle, display_name, avatar_url, is_claimed, created_at)
+SELECT c.id,
+    COALESCE((
+        SELECT MIN(t.platform) FROM tutorials_old t
+        WHERE t.creator_id = c.id
+        HAVING COUNT(DISTINCT t.platform) = 1
+    ), 'tiktok'),
+    c.tiktok_handle, c.display_name, c.avatar_url, c.is_claimed, c.created_at
+FROM creators_old c;
+
+CREATE TABLE tutorials (
+    id TEXT PRIMARY KEY,
+    creator_id TEXT NOT NULL REFERENCES creators(id) ON DELETE CASCADE,
+    platform TEXT NOT NULL,
+    source_url TEXT NOT NULL,
+    video_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    sound_type TEXT NOT NULL,
+    raw_transcription TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (platform, video_id)
+);
+
+INSERT INTO tutorials SELECT id, creator_id, platform, source_url, video_id, title, sound_type, raw_transcription, status, created_at, updated_at FROM tutorials_old;
+
+CREATE TABLE instructions_new (
+    id TEXT PRIMARY KEY,
+    tutorial_id TEXT NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    step_number INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    ableton_device TEXT,
+    parameters TEXT NOT NULL DEFAULT '{}' CHECK (json_valid(parameters)),
+    notes TEXT,
+    screenshot_url TEXT,
+    start_time REAL,
+    end_time REAL
+);
+
+INSERT INTO instructions_new SELECT id, tutorial_id, step_number, description, ableton_device, parameters, notes, screenshot_url, start_time, end_time FROM instructions;
+DROP TABLE instructions;
+ALTER TABLE instructions_new RENAME TO instructions;
+
+CREATE TABLE transcript_segments_new (
+    id TEXT PRIMARY KEY,
+    tutorial_id TEXT NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    segment_index INTEGER NOT NULL,
+    start_time REAL NOT NULL,
+    end_time REAL NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (tutorial_id, segment_index)
+);
+
+INSERT INTO transcript_segments_new SELECT id, tutorial_id, segment_index, start_time, end_time, text FROM transcript_segments;
+DROP TABLE transcript_segments;
+ALTER TABLE transcript_segments_new RENAME TO transcript_segments;
+
+DROP TABLE tutorials_old;
+DROP TABLE creators_old;
+
+CREATE INDEX IF NOT EXISTS idx_tutorials_creator_id ON tutorials(creator_id);
+CREATE INDEX IF NOT EXISTS idx_tutorials_status ON tutorials(status);
+CREATE INDEX IF NOT EXISTS idx_tutorials_sound_type ON tutorials(sound_type);
+CREATE INDEX IF NOT EXISTS idx_instructions_tutorial_id ON instructions(tutorial_id);
+
+CREATE TRIGGER IF NOT EXISTS tutorials_updated_at
+    AFTER UPDATE ON tutorials
+    FOR EACH ROW
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE tutorials SET updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now') WHERE id = NEW.id;
+END;
@@ -1,23 +1,29 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
-// Creator represents a TikTok content creator
+// Creator represents a content creator on one platform. The same handle on
+// two platforms is two creators.
 type Creator struct {
-	ID           string    `json:"id"`
-	TiktokHandle string    `json:"tiktok_handle"`
-	DisplayName  string    `json:"display_name"`
-	AvatarURL    string    `json:"avatar_url,omitempty"`
-	IsClaimed    bool      `json:"is_claimed"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	Platform    string    `json:"platform"` // tiktok, youtube, instagram, upload
+	Handle      string    `json:"handle"`   // unique per platform
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	IsClaimed   bool      `json:"is_claimed"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
-// Tutorial represents a transcribed TikTok video
+// Tutorial represents a transcribed TikTok, YouTube or Instagram video
 type Tutorial struct {
 	ID              string        `json:"id"`
 	CreatorID       string        `json:"creator_id"`
-	TiktokURL       string        `json:"tiktok_url"`
-	TiktokVideoID   string        `json:"tiktok_video_id"`
+	Platform        string        `json:"platform"` // tiktok, youtube, instagram, upload
+	SourceURL       string        `json:"source_url"`
+	VideoID         string        `json:"video_id"` // unique per platform
 	Title           string        `json:"title"`
 	SoundType       string        `json:"sound_type"`
 	RawTranscription string       `json:"raw_transcription"`
@@ -28,21 +34,98 @@ type Tutorial struct {
 	// Populated on fetch
 	Creator      *Creator      `json:"creator,omitempty"`
 	Instructions []Instruction `json:"instructions,omitempty"`
+
+	// Populated when fetching a single tutorial
+	Segments []TranscriptSegment `json:"transcript_segments,omitempty"`
+}
+
+// TranscriptSegment is a timestamped stretch of a tutorial's transcription
+type TranscriptSegment struct {
+	ID           string  `json:"id,omitempty"`
+	TutorialID   string  `json:"tutorial_id,omitempty"`
+	SegmentIndex int     `json:"segment_index"`
+	StartTime    float64 `json:"start_time"` // seconds into the video
+	EndTime      float64 `json:"end_time"`
+	Text         string  `json:"text"`
 }
 
 // Instruction represents a single step in a sound design recipe
 type Instruction struct {
-	ID            string            `json:"id"`
-	TutorialID    string            `json:"tutorial_id"`
-	StepNumber    int               `json:"step_number"`
-	Description   string            `json:"description"`
-	AbletonDevice string            `json:"ableton_device,omitempty"`
-	Parameters    map[string]string `json:"parameters,omitempty"`
-	Notes         string            `json:"notes,omitempty"`
-	ScreenshotURL string            `json:"screenshot_url,omitempty"`
+	ID            string                    `json:"id"`
+	TutorialID    string                    `json:"tutorial_id"`
+	StepNumber    int                       `json:"step_number"`
+	Description   string                    `json:"description"`
+	AbletonDevice string                    `json:"ableton_device,omitempty"`
+	Parameters    map[string]ParameterValue `json:"parameters,omitempty"`
+	Notes         string                    `json:"notes,omitempty"`
+	ScreenshotURL string                    `json:"screenshot_url,omitempty"`
+	StartTime     *float64                  `json:"start_time,omitempty"` // seconds into the video where the step is explained
+	EndTime       *float64                  `json:"end_time,omitempty"`
+}
+
+// Units that parameter values are normalized to
+const (
+	UnitHertz     = "Hz"
+	UnitDecibels  = "dB"
+	UnitPercent   = "%"
+	UnitMillis    = "ms"
+	UnitSemitones = "st"
+	UnitCents     = "ct"
+	UnitRatio     = "ratio" // compression ratio, 4:1 is 4
+	UnitBPM       = "bpm"
+	UnitNote      = "note" // note length as a fraction of a bar, 1/16 is 0.0625
+)
+
+// ParameterValue is a parameter setting as the creator said it, plus the
+// number and unit it was normalized to when that could be worked out
+type ParameterValue struct {
+	Value      *float64 `json:"value,omitempty"`
+	Unit       string   `json:"unit,omitempty"`
+	Raw        string   `json:"raw"`
+	Confidence float64  `json:"confidence"` // 0 when nothing could be parsed, 1 for an exact value and unit
+
+	// Set when the device has a parameter schema
+	ParsedKey   string   `json:"parsed_key,omitempty"` // the parser's name for the parameter, when it was renamed
+	Min         *float64 `json:"min,omitempty"`        // the parameter's allowed range
+	Max         *float64 `json:"max,omitempty"`
+	NeedsReview bool     `json:"needs_review,omitempty"` // the key matched none of the device's parameters, or the value is in the wrong unit or out of range
+}
+
+// UnmarshalJSON also accepts a plain string, the format parameters were
+// stored in before values were typed
+func (v *ParameterValue) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*v = ParameterValue{Raw: raw}
+		return nil
+	}
+
+	type plain ParameterValue
+	return json.Unmarshal(data, (*plain)(v))
 }
 
-// TranscribeRequest is the API request to transcribe a TikTok
+// Device is an Ableton stock device or third-party plugin from the catalog
+type Device struct {
+	Name        string            `json:"name"`
+	Vendor      string            `json:"vendor,omitempty"`
+	Category    string            `json:"category,omitempty"` // synth, sampler, drums, eq, dynamics, distortion, ...
+	Aliases     []string          `json:"aliases,omitempty"`
+	Parameters  []DeviceParameter `json:"parameters,omitempty"`
+	Catalogued  bool              `json:"catalogued"` // false for names only seen in recipes
+	RecipeCount int               `json:"recipe_count"`
+}
+
+// DeviceParameter is one control on a device, with the range it accepts in
+// the units parameter values are normalized to
+type DeviceParameter struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Unit    string   `json:"unit,omitempty"`
+	Min     *float64 `json:"min,omitempty"` // nil for switches and menus
+	Max     *float64 `json:"max,omitempty"`
+}
+
+// TranscribeRequest is the API request to transcribe a video
 type TranscribeRequest struct {
 	URL string `json:"url"`
 }
@@ -52,22 +135,171 @@ type TranscribeResponse struct {
 	Success  bool      `json:"success"`
 	Message  string    `json:"message,omitempty"`
 	Tutorial *Tutorial `json:"tutorial,omitempty"`
+	Job      *Job      `json:"job,omitempty"`
+}
+
+// JobStage is the step a transcription job is currently on
+type JobStage string
+
+const (
+	JobStageQueued       JobStage = "queued"
+	JobStageExtracting   JobStage = "extracting"
+	JobStageTranscribing JobStage = "transcribing"
+	JobStageParsing      JobStage = "parsing"
+	JobStageSaving       JobStage = "saving"
+	JobStageCompleted    JobStage = "completed"
+	JobStageFailed       JobStage = "failed"
+)
+
+// Done reports whether the job has finished, successfully or not
+func (s JobStage) Done() bool {
+	return s == JobStageCompleted || s == JobStageFailed
+}
+
+// Job tracks a transcription request processed in the background. Jobs
+// have either a URL or an uploaded file.
+type Job struct {
+	ID         string     `json:"id"`
+	URL        string     `json:"url,omitempty"`
+	Upload     *JobUpload `json:"upload,omitempty"`
+	Stage      JobStage   `json:"stage"`
+	Error      string     `json:"error,omitempty"`
+	ErrorCode  string     `json:"error_code,omitempty"` // e.g. JobErrorNotSoundDesign
+	TutorialID string     `json:"tutorial_id,omitempty"`
+	Duplicate  bool       `json:"duplicate,omitempty"` // tutorial already existed
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
-// ParsedRecipe is the structured output from Claude
+// JobErrorNotSoundDesign is the error_code of jobs whose video turned out
+// not to be a sound design tutorial
+const JobErrorNotSoundDesign = "not_sound_design"
+
+// JobUpload is a video or audio file uploaded for transcription, with the
+// creator details a URL would otherwise provide
+type JobUpload struct {
+	FileID        string  `json:"file_id"` // names the stored file, unique per upload
+	Hash          string  `json:"hash"`    // of the file's contents, the tutorial's video ID
+	FileName      string  `json:"file_name"`
+	Duration      float64 `json:"duration"` // seconds
+	CreatorHandle string  `json:"creator_handle"`
+	CreatorName   string  `json:"creator_name"`
+}
+
+// IngestVideoStatus is where a video in a profile ingest stands
+type IngestVideoStatus string
+
+const (
+	IngestVideoSkipped        IngestVideoStatus = "skipped" // already transcribed
+	IngestVideoPending        IngestVideoStatus = "pending"
+	IngestVideoProcessing     IngestVideoStatus = "processing"
+	IngestVideoAccepted       IngestVideoStatus = "accepted" // saved as a new tutorial
+	IngestVideoNotSoundDesign IngestVideoStatus = "not_sound_design"
+	IngestVideoRejected       IngestVideoStatus = "rejected" // failed to download, transcribe or parse
+)
+
+// Ingest is a bulk transcription of a creator's profile. Counts cover the
+// videos found on the profile; Pending and Processing reach zero once the
+// ingest completes.
+type Ingest struct {
+	Handle         string        `json:"handle"`
+	Done           bool          `json:"done"`
+	Total          int           `json:"total"`
+	Skipped        int           `json:"skipped"`
+	Pending        int           `json:"pending"`
+	Processing     int           `json:"processing"`
+	Accepted       int           `json:"accepted"`
+	NotSoundDesign int           `json:"not_sound_design"`
+	Rejected       int           `json:"rejected"`
+	Videos         []IngestVideo `json:"videos"`
+	StartedAt      time.Time     `json:"started_at"`
+	FinishedAt     *time.Time    `json:"finished_at,omitempty"`
+}
+
+// IngestVideo is one video found on the profile
+type IngestVideo struct {
+	VideoID    string            `json:"video_id"`
+	URL        string            `json:"url"`
+	Title      string            `json:"title,omitempty"`
+	Status     IngestVideoStatus `json:"status"`
+	JobID      string            `json:"job_id,omitempty"`
+	TutorialID string            `json:"tutorial_id,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// JobEventType describes what happened to a job
+type JobEventType string
+
+const (
+	JobEventStageStarted   JobEventType = "stage_started"
+	JobEventStageCompleted JobEventType = "stage_completed"
+	JobEventStageFailed    JobEventType = "stage_failed"
+	JobEventCompleted      JobEventType = "job_completed"
+	JobEventFailed         JobEventType = "job_failed"
+)
+
+// Terminal reports whether no further events follow this one
+func (t JobEventType) Terminal() bool {
+	return t == JobEventCompleted || t == JobEventFailed
+}
+
+// JobEvent is a progress update emitted while a job runs
+type JobEvent struct {
+	ID      int                    `json:"id"` // position in the job's event history, starting at 1
+	Type    JobEventType           `json:"type"`
+	Stage   JobStage               `json:"stage,omitempty"`
+	Message string                 `json:"message"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+	Time    time.Time              `json:"time"`
+}
+
+// ParsedRecipe is the structured output from Claude. The desc tags become
+// field descriptions in the JSON schema the parser gives the model.
 type ParsedRecipe struct {
-	Title        string              `json:"title"`
-	SoundType    string              `json:"sound_type"`
-	CreatorName  string              `json:"creator_name"`
-	Instructions []ParsedInstruction `json:"instructions"`
-	IsSoundDesign bool               `json:"is_sound_design"`
+	Title         string              `json:"title" desc:"Short descriptive title for this sound, e.g. 'Massive Reese Bass' or 'Plucky Arp'"`
+	SoundType     string              `json:"sound_type" desc:"Category of sound, e.g. bass, lead, pad, arp, kick, snare, fx or chord"`
+	CreatorName   string              `json:"creator_name" desc:"The creator's display name"`
+	Instructions  []ParsedInstruction `json:"instructions" desc:"Steps in order; empty if this isn't a sound design tutorial"`
+	IsSoundDesign bool                `json:"is_sound_design" desc:"False if this isn't actually a sound design tutorial"`
 }
 
 // ParsedInstruction is a single instruction from Claude parsing
 type ParsedInstruction struct {
-	StepNumber    int               `json:"step_number"`
-	Description   string            `json:"description"`
-	AbletonDevice string            `json:"ableton_device,omitempty"`
-	Parameters    map[string]string `json:"parameters,omitempty"`
-	Notes         string            `json:"notes,omitempty"`
+	StepNumber    int               `json:"step_number" desc:"1-based position of the step"`
+	Description   string            `json:"description" desc:"Clear instruction of what to do"`
+	AbletonDevice string            `json:"ableton_device,omitempty" desc:"Ableton device or plugin used, e.g. Wavetable, Operator, Serum or Saturator"`
+	Parameters    ParsedParameters  `json:"parameters,omitempty" desc:"Parameter values mentioned, keyed by parameter name, with units where given (e.g. '800 Hz', '-6 dB', '40%')"`
+	Notes         string            `json:"notes,omitempty" desc:"Any additional tips or context"`
+	StartTime     *float64          `json:"start_time,omitempty" desc:"Seconds into the video where the step is explained"`
+	EndTime       *float64          `json:"end_time,omitempty" desc:"Seconds into the video where the explanation ends"`
+}
+
+// ParsedParameters are parameter values as the model gave them, keyed by
+// parameter name
+type ParsedParameters map[string]string
+
+// UnmarshalJSON also accepts bare numbers and booleans, which models send
+// despite the schema, keeping them as written
+func (p *ParsedParameters) UnmarshalJSON(data []byte) error {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if values == nil {
+		*p = nil
+		return nil
+	}
+
+	*p = make(ParsedParameters, len(values))
+	for key, value := range values {
+		if string(value) == "null" {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
+		}
+		(*p)[key] = s
+	}
+	return nil
 }
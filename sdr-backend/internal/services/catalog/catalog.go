@@ -0,0 +1,224 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// Catalog maps the many ways a device gets named ("serum", "Xfer Serum",
+// "OTT (Multiband Dynamics)") onto one canonical device
+type Catalog struct {
+	devices    []models.Device
+	index      map[string]int   // match key -> position in devices
+	vendors    []string         // vendor match keys, longest first
+	parameters []map[string]int // per device, match key -> position in its Parameters
+}
+
+// New builds a catalog from devices. When two devices share a name or
+// alias, the first one wins.
+func New(devices []models.Device) *Catalog {
+	c := &Catalog{
+		devices:    devices,
+		index:      map[string]int{},
+		parameters: make([]map[string]int, len(devices)),
+	}
+
+	vendors := map[string]bool{}
+	for i, d := range devices {
+		addNames(c.index, i, d.Name, d.Aliases)
+
+		c.parameters[i] = map[string]int{}
+		for j, p := range d.Parameters {
+			addNames(c.parameters[i], j, p.Name, p.Aliases)
+		}
+
+		// Match both "Xfer Records Serum" and "Xfer Serum"
+		if d.Vendor != "" {
+			vendors[matchKey(d.Vendor)] = true
+			vendors[matchKey(strings.Fields(d.Vendor)[0])] = true
+		}
+	}
+
+	for vendor := range vendors {
+		c.vendors = append(c.vendors, vendor)
+	}
+	sort.Slice(c.vendors, func(i, j int) bool {
+		return len(c.vendors[i]) > len(c.vendors[j])
+	})
+
+	return c
+}
+
+// addNames indexes a name and its aliases under position i, keeping
+// earlier entries on collisions
+func addNames(index map[string]int, i int, name string, aliases []string) {
+	for _, n := range append([]string{name}, aliases...) {
+		if _, taken := index[matchKey(n)]; !taken {
+			index[matchKey(n)] = i
+		}
+	}
+}
+
+// Default returns the built-in catalog of Ableton stock devices and common
+// plugins, with parameter schemas for the most used ones
+func Default() *Catalog {
+	devices := append([]models.Device(nil), builtin...)
+	for i := range devices {
+		devices[i].Parameters = builtinParameters[devices[i].Name]
+	}
+	return New(devices)
+}
+
+// Devices returns every catalogued device
+func (c *Catalog) Devices() []models.Device {
+	return append([]models.Device(nil), c.devices...)
+}
+
+// Lookup finds the device a name refers to. A parenthetical is tried
+// before the rest of the name, since it usually says which device was
+// meant, and a leading vendor name is ignored.
+func (c *Catalog) Lookup(name string) (models.Device, bool) {
+	if i, ok := c.find(name); ok {
+		return c.devices[i], true
+	}
+	return models.Device{}, false
+}
+
+// find returns the position of the device name refers to
+func (c *Catalog) find(name string) (int, bool) {
+	candidates := []string{name}
+	if open := strings.IndexByte(name, '('); open >= 0 {
+		inner, _, _ := strings.Cut(name[open+1:], ")")
+		candidates = append(candidates, inner, name[:open])
+	}
+
+	for _, candidate := range candidates {
+		key := matchKey(candidate)
+		if key == "" {
+			continue
+		}
+		if i, ok := c.index[key]; ok {
+			return i, true
+		}
+		for _, vendor := range c.vendors {
+			if rest, found := strings.CutPrefix(key, vendor); found && rest != "" {
+				if i, ok := c.index[rest]; ok {
+					return i, true
+				}
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// Normalize returns the canonical name for a device, or the trimmed input
+// if it isn't catalogued
+func (c *Catalog) Normalize(name string) string {
+	if device, ok := c.Lookup(name); ok {
+		return device.Name
+	}
+	return strings.TrimSpace(name)
+}
+
+// Parameter finds the parameter of device that key refers to. A leading
+// device name is ignored, so "Wavetable Filter Cutoff" matches Wavetable's
+// "Filter 1 Frequency".
+func (c *Catalog) Parameter(device, key string) (models.DeviceParameter, bool) {
+	i, ok := c.find(device)
+	if !ok {
+		return models.DeviceParameter{}, false
+	}
+
+	k := matchKey(key)
+	if j, ok := c.parameters[i][k]; ok {
+		return c.devices[i].Parameters[j], true
+	}
+	if rest, found := strings.CutPrefix(k, matchKey(c.devices[i].Name)); found && rest != "" {
+		if j, ok := c.parameters[i][rest]; ok {
+			return c.devices[i].Parameters[j], true
+		}
+	}
+
+	return models.DeviceParameter{}, false
+}
+
+// NormalizeParameters renames the parameters of an instruction on device to
+// the device's canonical parameter names and attaches their ranges. Keys
+// that match none of the device's parameters, or values outside the range,
+// are kept but flagged for review. Devices without a parameter schema are
+// left alone.
+func (c *Catalog) NormalizeParameters(device string, params map[string]models.ParameterValue) map[string]models.ParameterValue {
+	i, ok := c.find(device)
+	if !ok || len(c.devices[i].Parameters) == 0 || len(params) == 0 {
+		return params
+	}
+
+	// Sorted so that when two keys name the same parameter, which one keeps
+	// it doesn't change between runs
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	normalized := make(map[string]models.ParameterValue, len(params))
+	for _, key := range keys {
+		value := params[key]
+
+		param, ok := c.Parameter(device, key)
+		if _, taken := normalized[param.Name]; !ok || taken {
+			value.NeedsReview = true
+			normalized[unusedKey(normalized, key)] = value
+			continue
+		}
+
+		if key != param.Name {
+			value.ParsedKey = key
+		}
+		value.Min, value.Max = param.Min, param.Max
+
+		// A bare number is taken to be in the parameter's unit
+		if value.Value != nil && value.Unit == "" {
+			value.Unit = param.Unit
+		}
+		// A value in another unit can't be checked against the range, or
+		// used as the parameter's setting
+		if value.Value != nil && (value.Unit != param.Unit || outOfRange(*value.Value, param)) {
+			value.NeedsReview = true
+		}
+
+		normalized[param.Name] = value
+	}
+
+	return normalized
+}
+
+func outOfRange(value float64, param models.DeviceParameter) bool {
+	return (param.Min != nil && value < *param.Min) || (param.Max != nil && value > *param.Max)
+}
+
+// unusedKey returns key, suffixed if a canonical name already took it
+func unusedKey(params map[string]models.ParameterValue, key string) string {
+	candidate := key
+	for n := 2; ; n++ {
+		if _, taken := params[candidate]; !taken {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (%d)", key, n)
+	}
+}
+
+// matchKey lowercases name and drops everything but letters and digits
+func matchKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
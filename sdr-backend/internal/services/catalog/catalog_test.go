@@ -0,0 +1,215 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+func testCatalog() *Catalog {
+	return New([]models.Device{
+		device("Serum", "Xfer Records", CategorySynth, "Serum 1"),
+		device("OTT", "Xfer Records", CategoryDynamics),
+		device("Wavetable", ableton, CategorySynth),
+		device("Reverb", ableton, CategoryReverb, "Verb"),
+		device("ValhallaVintageVerb", "Valhalla DSP", CategoryReverb, "Verb"),
+	})
+}
+
+func TestLookup(t *testing.T) {
+	c := testCatalog()
+
+	tests := []struct {
+		name string
+		want string // empty when not catalogued
+	}{
+		{"Serum", "Serum"},
+		{"serum", "Serum"},
+		{"SERUM 1", "Serum"},
+		{"Xfer Serum", "Serum"},
+		{"Xfer Records Serum", "Serum"},
+		{"xfer-records serum", "Serum"},
+		{"Serum (Xfer)", "Serum"},
+		{"Multiband Compressor (OTT)", "OTT"},
+		{"wave table", "Wavetable"},
+		{"Verb", "Reverb"}, // first device with the alias wins
+		{"Valhalla VintageVerb", "ValhallaVintageVerb"},
+		{"Xfer", ""},
+		{"Diva", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device, ok := c.Lookup(tt.name)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("Lookup(%q) = %q, want no match", tt.name, device.Name)
+				}
+				return
+			}
+			if !ok || device.Name != tt.want {
+				t.Errorf("Lookup(%q) = %q, %v, want %q", tt.name, device.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	c := testCatalog()
+
+	tests := map[string]string{
+		"Xfer Serum": "Serum",
+		"  ott ":     "OTT",
+		"  Diva ":    "Diva",
+	}
+	for name, want := range tests {
+		if got := c.Normalize(name); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDefaultNamesAreUnique(t *testing.T) {
+	seen := map[string]string{}
+	for _, d := range Default().Devices() {
+		key := matchKey(d.Name)
+		if other, ok := seen[key]; ok {
+			t.Errorf("%q and %q have the same match key %q", other, d.Name, key)
+		}
+		seen[key] = d.Name
+	}
+}
+
+func value(v float64, unit string) models.ParameterValue {
+	return models.ParameterValue{Value: &v, Unit: unit}
+}
+
+// wantParam is what a normalized parameter should carry
+type wantParam struct {
+	parsedKey   string
+	unit        string
+	needsReview bool
+}
+
+func TestNormalizeParameters(t *testing.T) {
+	c := Default()
+
+	tests := []struct {
+		name   string
+		params map[string]models.ParameterValue
+		want   map[string]wantParam
+	}{
+		{
+			name:   "alias renamed",
+			params: map[string]models.ParameterValue{"Cutoff": value(2000, models.UnitHertz)},
+			want: map[string]wantParam{
+				"Filter 1 Frequency": {parsedKey: "Cutoff", unit: models.UnitHertz},
+			},
+		},
+		{
+			name:   "device name prefix ignored",
+			params: map[string]models.ParameterValue{"Wavetable Filter Cutoff": value(500, models.UnitHertz)},
+			want: map[string]wantParam{
+				"Filter 1 Frequency": {parsedKey: "Wavetable Filter Cutoff", unit: models.UnitHertz},
+			},
+		},
+		{
+			name:   "bare number takes the parameter's unit",
+			params: map[string]models.ParameterValue{"WT Pos": value(50, "")},
+			want: map[string]wantParam{
+				"Osc 1 Position": {parsedKey: "WT Pos", unit: models.UnitPercent},
+			},
+		},
+		{
+			name:   "out of range",
+			params: map[string]models.ParameterValue{"Resonance": value(150, models.UnitPercent)},
+			want: map[string]wantParam{
+				"Filter 1 Resonance": {parsedKey: "Resonance", unit: models.UnitPercent, needsReview: true},
+			},
+		},
+		{
+			name:   "wrong unit",
+			params: map[string]models.ParameterValue{"Cutoff": value(40, models.UnitPercent)},
+			want: map[string]wantParam{
+				"Filter 1 Frequency": {parsedKey: "Cutoff", unit: models.UnitPercent, needsReview: true},
+			},
+		},
+		{
+			name:   "unknown key kept for review",
+			params: map[string]models.ParameterValue{"Wobble": value(3, "")},
+			want: map[string]wantParam{
+				"Wobble": {needsReview: true},
+			},
+		},
+		{
+			name: "second alias for the same parameter",
+			params: map[string]models.ParameterValue{
+				"Cutoff":      value(2000, models.UnitHertz),
+				"Filter Freq": value(800, models.UnitHertz),
+			},
+			want: map[string]wantParam{
+				"Filter 1 Frequency": {parsedKey: "Cutoff", unit: models.UnitHertz},
+				"Filter Freq":        {unit: models.UnitHertz, needsReview: true},
+			},
+		},
+		{
+			name: "canonical name already taken is suffixed",
+			params: map[string]models.ParameterValue{
+				"Cutoff":             value(2000, models.UnitHertz),
+				"Filter 1 Frequency": value(800, models.UnitHertz),
+			},
+			want: map[string]wantParam{
+				"Filter 1 Frequency":     {parsedKey: "Cutoff", unit: models.UnitHertz},
+				"Filter 1 Frequency (2)": {unit: models.UnitHertz, needsReview: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.NormalizeParameters("Wavetable", tt.params)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d parameters %v, want %d", len(got), got, len(tt.want))
+			}
+			for key, want := range tt.want {
+				v, ok := got[key]
+				if !ok {
+					t.Fatalf("missing %q in %v", key, got)
+				}
+				if v.ParsedKey != want.parsedKey {
+					t.Errorf("%s: ParsedKey = %q, want %q", key, v.ParsedKey, want.parsedKey)
+				}
+				if v.Unit != want.unit {
+					t.Errorf("%s: Unit = %q, want %q", key, v.Unit, want.unit)
+				}
+				if v.NeedsReview != want.needsReview {
+					t.Errorf("%s: NeedsReview = %v, want %v", key, v.NeedsReview, want.needsReview)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeParametersWithoutSchema(t *testing.T) {
+	c := Default()
+	params := map[string]models.ParameterValue{"Wobble": value(3, "")}
+
+	for _, device := range []string{"Drift", "Diva"} {
+		got := c.NormalizeParameters(device, params)
+		if len(got) != 1 || got["Wobble"].NeedsReview {
+			t.Errorf("NormalizeParameters(%q) = %v, want the parameters unchanged", device, got)
+		}
+	}
+}
+
+func TestNormalizeParametersSetsRange(t *testing.T) {
+	got := Default().NormalizeParameters("Wavetable", map[string]models.ParameterValue{
+		"Cutoff": value(2000, models.UnitHertz),
+	})
+
+	v := got["Filter 1 Frequency"]
+	if v.Min == nil || v.Max == nil || *v.Min != 20 || *v.Max != 20000 {
+		t.Errorf("range = %v..%v, want 20..20000", v.Min, v.Max)
+	}
+}
@@ -0,0 +1,144 @@
+package catalog
+
+import "github.com/camwick/sdr-backend/internal/models"
+
+// Device categories
+const (
+	CategorySynth      = "synth"
+	CategorySampler    = "sampler"
+	CategoryDrums      = "drums"
+	CategoryEQ         = "eq"
+	CategoryFilter     = "filter"
+	CategoryDynamics   = "dynamics"
+	CategoryDistortion = "distortion"
+	CategoryReverb     = "reverb"
+	CategoryDelay      = "delay"
+	CategoryModulation = "modulation"
+	CategoryPitch      = "pitch"
+	CategoryUtility    = "utility"
+	CategoryMIDI       = "midi"
+)
+
+const ableton = "Ableton"
+
+// builtin lists Ableton Live's stock devices and the third-party plugins
+// that come up most in sound design tutorials. Aliases only need to cover
+// spellings that differ by more than case, spacing, punctuation or a vendor
+// prefix, which are ignored when matching.
+var builtin = []models.Device{
+	// Ableton instruments
+	device("Wavetable", ableton, CategorySynth),
+	device("Operator", ableton, CategorySynth, "FM synth"),
+	device("Analog", ableton, CategorySynth),
+	device("Drift", ableton, CategorySynth),
+	device("Meld", ableton, CategorySynth),
+	device("Collision", ableton, CategorySynth),
+	device("Tension", ableton, CategorySynth),
+	device("Electric", ableton, CategorySynth),
+	device("Simpler", ableton, CategorySampler),
+	device("Sampler", ableton, CategorySampler),
+	device("Drum Rack", ableton, CategoryDrums),
+	device("Drum Sampler", ableton, CategoryDrums),
+	device("Impulse", ableton, CategoryDrums),
+	device("Instrument Rack", ableton, CategoryUtility),
+
+	// Ableton audio effects
+	device("EQ Eight", ableton, CategoryEQ, "EQ 8"),
+	device("EQ Three", ableton, CategoryEQ, "EQ 3"),
+	device("Channel EQ", ableton, CategoryEQ),
+	device("Auto Filter", ableton, CategoryFilter),
+	device("Compressor", ableton, CategoryDynamics),
+	device("Glue Compressor", ableton, CategoryDynamics, "Glue"),
+	device("Multiband Dynamics", ableton, CategoryDynamics, "Multiband", "MBD"),
+	device("Limiter", ableton, CategoryDynamics),
+	device("Gate", ableton, CategoryDynamics),
+	device("Drum Buss", ableton, CategoryDynamics, "Drum Bus"),
+	device("Saturator", ableton, CategoryDistortion),
+	device("Overdrive", ableton, CategoryDistortion),
+	device("Pedal", ableton, CategoryDistortion),
+	device("Roar", ableton, CategoryDistortion),
+	device("Redux", ableton, CategoryDistortion, "Bitcrusher"),
+	device("Erosion", ableton, CategoryDistortion),
+	device("Dynamic Tube", ableton, CategoryDistortion),
+	device("Vinyl Distortion", ableton, CategoryDistortion),
+	device("Amp", ableton, CategoryDistortion),
+	device("Cabinet", ableton, CategoryDistortion),
+	device("Reverb", ableton, CategoryReverb),
+	device("Hybrid Reverb", ableton, CategoryReverb),
+	device("Delay", ableton, CategoryDelay, "Simple Delay", "Ping Pong Delay"),
+	device("Echo", ableton, CategoryDelay),
+	device("Filter Delay", ableton, CategoryDelay),
+	device("Grain Delay", ableton, CategoryDelay),
+	device("Beat Repeat", ableton, CategoryDelay),
+	device("Chorus-Ensemble", ableton, CategoryModulation, "Chorus"),
+	device("Phaser-Flanger", ableton, CategoryModulation, "Phaser", "Flanger"),
+	device("Auto Pan", ableton, CategoryModulation),
+	device("Frequency Shifter", ableton, CategoryModulation, "Freq Shifter"),
+	device("Corpus", ableton, CategoryModulation),
+	device("Resonators", ableton, CategoryModulation, "Resonator"),
+	device("Spectral Resonator", ableton, CategoryModulation),
+	device("Spectral Time", ableton, CategoryDelay),
+	device("Vocoder", ableton, CategoryModulation),
+	device("Shifter", ableton, CategoryPitch, "Pitch Shifter"),
+	device("LFO", ableton, CategoryModulation),
+	device("Shaper", ableton, CategoryModulation),
+	device("Envelope Follower", ableton, CategoryModulation),
+	device("Utility", ableton, CategoryUtility),
+	device("Tuner", ableton, CategoryUtility),
+	device("Spectrum", ableton, CategoryUtility),
+	device("Audio Effect Rack", ableton, CategoryUtility, "Effect Rack"),
+
+	// Ableton MIDI effects
+	device("Arpeggiator", ableton, CategoryMIDI, "Arp"),
+	device("Chord", ableton, CategoryMIDI),
+	device("Scale", ableton, CategoryMIDI),
+	device("Note Length", ableton, CategoryMIDI),
+	device("Random", ableton, CategoryMIDI),
+	device("Velocity", ableton, CategoryMIDI),
+	device("Pitch", ableton, CategoryMIDI),
+
+	// Third-party synths
+	device("Serum", "Xfer Records", CategorySynth, "Serum 1"),
+	device("Serum 2", "Xfer Records", CategorySynth),
+	device("Vital", "Vital Audio", CategorySynth, "Vitalium"),
+	device("Massive", "Native Instruments", CategorySynth, "NI Massive"),
+	device("Massive X", "Native Instruments", CategorySynth),
+	device("Sylenth1", "LennarDigital", CategorySynth, "Sylenth"),
+	device("Phase Plant", "Kilohearts", CategorySynth),
+	device("Pigments", "Arturia", CategorySynth),
+	device("Diva", "u-he", CategorySynth),
+	device("Zebra2", "u-he", CategorySynth, "Zebra"),
+	device("Spire", "Reveal Sound", CategorySynth),
+	device("Omnisphere", "Spectrasonics", CategorySynth),
+
+	// Third-party effects
+	device("OTT", "Xfer Records", CategoryDynamics, "Xfer OTT"),
+	device("LFOTool", "Xfer Records", CategoryModulation),
+	device("Pro-Q 3", "FabFilter", CategoryEQ, "Pro-Q"),
+	device("Pro-C 2", "FabFilter", CategoryDynamics, "Pro-C"),
+	device("Pro-L 2", "FabFilter", CategoryDynamics, "Pro-L"),
+	device("Saturn 2", "FabFilter", CategoryDistortion, "Saturn"),
+	device("Pro-R 2", "FabFilter", CategoryReverb, "Pro-R"),
+	device("ValhallaVintageVerb", "Valhalla DSP", CategoryReverb, "VintageVerb"),
+	device("ValhallaSupermassive", "Valhalla DSP", CategoryReverb, "Supermassive"),
+	device("ValhallaShimmer", "Valhalla DSP", CategoryReverb, "Shimmer"),
+	device("Decapitator", "Soundtoys", CategoryDistortion),
+	device("EchoBoy", "Soundtoys", CategoryDelay),
+	device("Little AlterBoy", "Soundtoys", CategoryPitch, "AlterBoy"),
+	device("ShaperBox 3", "Cableguys", CategoryModulation, "ShaperBox"),
+	device("Kickstart 2", "Nicky Romero", CategoryDynamics, "Kickstart"),
+	device("Trash 2", "iZotope", CategoryDistortion, "Trash"),
+	device("Ozone", "iZotope", CategoryDynamics),
+	device("CamelCrusher", "Camel Audio", CategoryDistortion),
+	device("Portal", "Output", CategoryModulation),
+}
+
+func device(name, vendor, category string, aliases ...string) models.Device {
+	return models.Device{
+		Name:       name,
+		Vendor:     vendor,
+		Category:   category,
+		Aliases:    aliases,
+		Catalogued: true,
+	}
+}
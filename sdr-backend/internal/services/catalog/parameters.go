@@ -0,0 +1,182 @@
+package catalog
+
+import "github.com/camwick/sdr-backend/internal/models"
+
+// builtinParameters holds parameter schemas for the devices tutorials tweak
+// most, keyed by device name. Names follow the device's own labels, and
+// ranges are in the units parameter values are normalized to. As with
+// devices, aliases only need to cover spellings that differ by more than
+// case, spacing or punctuation.
+var builtinParameters = map[string][]models.DeviceParameter{
+	"Wavetable": {
+		knob("Osc 1 Position", models.UnitPercent, 0, 100, "Osc 1 Pos", "Wavetable Position", "Wave Position", "WT Position", "WT Pos", "Position"),
+		knob("Osc 1 Transpose", models.UnitSemitones, -48, 48, "Osc 1 Semi", "Osc 1 Pitch", "Osc 1 Transp"),
+		knob("Osc 1 Gain", models.UnitPercent, 0, 100, "Osc 1 Level", "Osc 1 Volume"),
+		knob("Osc 2 Position", models.UnitPercent, 0, 100, "Osc 2 Pos"),
+		knob("Osc 2 Transpose", models.UnitSemitones, -48, 48, "Osc 2 Semi", "Osc 2 Pitch", "Osc 2 Transp"),
+		knob("Osc 2 Gain", models.UnitPercent, 0, 100, "Osc 2 Level", "Osc 2 Volume"),
+		knob("Sub Gain", models.UnitPercent, 0, 100, "Sub", "Sub Level", "Sub Osc"),
+		knob("Filter 1 Frequency", models.UnitHertz, 20, 20000, "Filter 1 Freq", "Filter 1 Cutoff", "Filter Frequency", "Filter Freq", "Filter Cutoff", "Flt Freq", "Flt 1 Freq", "Cutoff", "Frequency"),
+		knob("Filter 1 Resonance", models.UnitPercent, 0, 100, "Filter 1 Res", "Filter Resonance", "Filter Res", "Flt Res", "Resonance", "Res"),
+		knob("Filter 1 Drive", models.UnitDecibels, 0, 24, "Filter Drive", "Flt Drive", "Drive"),
+		choice("Filter 1 Type", "Filter Type", "Flt Type"),
+		knob("Filter 2 Frequency", models.UnitHertz, 20, 20000, "Filter 2 Freq", "Filter 2 Cutoff", "Flt 2 Freq"),
+		knob("Filter 2 Resonance", models.UnitPercent, 0, 100, "Filter 2 Res", "Flt 2 Res"),
+		knob("Amp Attack", models.UnitMillis, 0, 20000, "Amp Env Attack", "Env 1 Attack", "Attack"),
+		knob("Amp Decay", models.UnitMillis, 1, 60000, "Amp Env Decay", "Env 1 Decay", "Decay"),
+		knob("Amp Sustain", models.UnitPercent, 0, 100, "Amp Env Sustain", "Env 1 Sustain", "Sustain"),
+		knob("Amp Release", models.UnitMillis, 1, 60000, "Amp Env Release", "Env 1 Release", "Release"),
+		choice("Unison Mode", "Unison", "Unison Type"),
+		knob("Unison Voices", "", 2, 8, "Voices"),
+		knob("Unison Amount", models.UnitPercent, 0, 100, "Unison Detune", "Detune", "Spread"),
+		knob("Glide", models.UnitMillis, 0, 5000, "Portamento", "Glide Time"),
+		knob("Volume", models.UnitDecibels, -36, 6, "Main Volume", "Output", "Gain"),
+	},
+	"Operator": {
+		knob("Osc A Coarse", "", 0, 48, "Osc A Ratio", "A Coarse", "Operator A Coarse", "Oscillator A Coarse", "Coarse"),
+		knob("Osc A Fine", "", 0, 1000, "A Fine", "Operator A Fine", "Fine"),
+		knob("Osc B Coarse", "", 0, 48, "Osc B Ratio", "B Coarse", "Operator B Coarse", "Oscillator B Coarse"),
+		knob("Osc B Fine", "", 0, 1000, "B Fine", "Operator B Fine"),
+		knob("Osc C Coarse", "", 0, 48, "Osc C Ratio", "C Coarse", "Operator C Coarse", "Oscillator C Coarse"),
+		knob("Osc C Fine", "", 0, 1000, "C Fine", "Operator C Fine"),
+		knob("Osc D Coarse", "", 0, 48, "Osc D Ratio", "D Coarse", "Operator D Coarse", "Oscillator D Coarse"),
+		knob("Osc D Fine", "", 0, 1000, "D Fine", "Operator D Fine"),
+		choice("Osc A Wave", "A Wave", "Osc A Waveform", "Waveform", "Wave"),
+		knob("Algorithm", "", 1, 11, "Algo", "FM Algorithm"),
+		knob("Filter Frequency", models.UnitHertz, 30, 18500, "Filter Freq", "Filter Cutoff", "Flt Freq", "Cutoff", "Frequency"),
+		knob("Filter Resonance", models.UnitPercent, 0, 125, "Filter Res", "Flt Res", "Resonance", "Res"),
+		choice("Filter Type", "Flt Type"),
+		knob("Tone", models.UnitPercent, 0, 100),
+		knob("Transpose", models.UnitSemitones, -48, 48, "Pitch", "Semi"),
+		knob("Glide Time", models.UnitMillis, 0.5, 10000, "Glide", "Portamento"),
+		knob("Volume", models.UnitDecibels, -36, 6, "Output", "Gain"),
+	},
+	"Saturator": {
+		knob("Drive", models.UnitDecibels, -36, 36, "Input", "Input Drive", "Gain"),
+		choice("Type", "Curve", "Shape", "Mode", "Saturation Type"),
+		knob("Base", "", -100, 100),
+		knob("Frequency", models.UnitHertz, 30, 18500, "Color Frequency", "Freq"),
+		knob("Width", models.UnitPercent, 0, 100, "Color Width"),
+		knob("Depth", models.UnitDecibels, -36, 36, "Color Depth"),
+		knob("Output", models.UnitDecibels, -36, 0, "Output Gain", "Out", "Volume"),
+		knob("Dry/Wet", models.UnitPercent, 0, 100, "Mix", "Wet"),
+		choice("Soft Clip", "Clip"),
+	},
+	"Auto Filter": {
+		knob("Frequency", models.UnitHertz, 26, 19900, "Cutoff", "Filter Frequency", "Filter Cutoff", "Freq"),
+		knob("Resonance", models.UnitPercent, 0, 125, "Res", "Q"),
+		knob("Drive", models.UnitDecibels, 0, 24),
+		choice("Filter Type", "Type", "Mode"),
+		knob("Envelope Amount", models.UnitPercent, -100, 100, "Env Amount", "Envelope", "Env Mod"),
+		knob("LFO Amount", models.UnitPercent, 0, 100, "LFO Amt", "LFO Depth", "Amount"),
+		knob("LFO Rate", models.UnitHertz, 0.01, 40, "Rate", "LFO Speed"),
+		knob("Dry/Wet", models.UnitPercent, 0, 100, "Mix", "Wet"),
+	},
+	"Compressor": {
+		knob("Threshold", models.UnitDecibels, -70, 6, "Thresh"),
+		knob("Ratio", models.UnitRatio, 1, 100),
+		knob("Attack", models.UnitMillis, 0.01, 1000),
+		knob("Release", models.UnitMillis, 1, 3000),
+		knob("Knee", models.UnitDecibels, 0, 18),
+		knob("Output Gain", models.UnitDecibels, -36, 36, "Output", "Makeup", "Makeup Gain", "Gain"),
+		knob("Dry/Wet", models.UnitPercent, 0, 100, "Mix", "Wet"),
+		choice("Sidechain", "Sidechain Source", "Sidechain Input", "SC"),
+	},
+	"Utility": {
+		knob("Gain", models.UnitDecibels, -35, 35, "Volume", "Level"),
+		knob("Width", models.UnitPercent, 0, 400, "Stereo Width"),
+		choice("Mono", "Mono Switch"),
+		knob("Bass Mono Frequency", models.UnitHertz, 50, 500, "Bass Mono", "Bass Mono Freq"),
+		knob("Balance", models.UnitPercent, -100, 100, "Pan", "Panning"),
+	},
+	"Reverb": {
+		knob("Decay Time", models.UnitMillis, 200, 60000, "Decay", "Reverb Time", "Time", "Length"),
+		knob("Predelay", models.UnitMillis, 0.5, 250, "Pre Delay", "Pre-Delay"),
+		knob("Size", models.UnitPercent, 0.22, 500, "Room Size"),
+		knob("Diffusion", models.UnitPercent, 0, 100),
+		knob("Dry/Wet", models.UnitPercent, 0, 100, "Mix", "Wet"),
+		choice("Freeze", "Freeze Mode"),
+	},
+	"OTT": {
+		knob("Depth", models.UnitPercent, 0, 100, "Amount", "Mix", "Dry/Wet"),
+		knob("Time", models.UnitPercent, 0, 1000, "Speed"),
+		knob("Upward", models.UnitPercent, 0, 100, "Upwards", "Upward Compression", "Up"),
+		knob("Downward", models.UnitPercent, 0, 100, "Downwards", "Downward Compression", "Down"),
+		knob("In Gain", models.UnitDecibels, -24, 24, "Input", "Input Gain"),
+		knob("Out Gain", models.UnitDecibels, -24, 24, "Output", "Output Gain"),
+	},
+	"Serum": {
+		knob("Osc A WT Pos", "", 1, 256, "A WT Pos", "Osc A Position", "WT Position", "WT Pos", "Wavetable Position", "Position"),
+		knob("Osc B WT Pos", "", 1, 256, "B WT Pos", "Osc B Position"),
+		knob("Osc A Unison", "", 1, 16, "A Unison", "Unison", "Unison Voices", "Voices"),
+		knob("Osc A Detune", models.UnitPercent, 0, 100, "A Detune", "Unison Detune", "Detune"),
+		knob("Osc A Octave", models.UnitSemitones, -48, 48, "A Oct", "Octave"),
+		knob("Filter Cutoff", models.UnitHertz, 8, 22000, "Filter Frequency", "Filter Freq", "Flt Freq", "Cutoff", "Frequency"),
+		knob("Filter Res", models.UnitPercent, 0, 100, "Filter Resonance", "Resonance", "Res"),
+		knob("Filter Drive", models.UnitPercent, 0, 100, "Drive"),
+		choice("Filter Type", "Flt Type"),
+		knob("Env 1 Attack", models.UnitMillis, 0, 32000, "Amp Attack", "Attack"),
+		knob("Env 1 Decay", models.UnitMillis, 0, 32000, "Amp Decay", "Decay"),
+		knob("Env 1 Release", models.UnitMillis, 0, 32000, "Amp Release", "Release"),
+		knob("Porta", models.UnitMillis, 0, 2000, "Portamento", "Glide"),
+	},
+	"Vital": {
+		knob("Osc 1 Frame", "", 0, 256, "Osc 1 Position", "Osc 1 WT Pos", "Osc 1 Wave Frame", "Wavetable Position", "WT Position", "WT Pos", "Position", "Frame"),
+		knob("Osc 1 Transpose", models.UnitSemitones, -48, 48, "Osc 1 Pitch", "Osc 1 Semi", "Transpose", "Pitch"),
+		knob("Osc 1 Tune", models.UnitCents, -100, 100, "Osc 1 Fine", "Fine Tune", "Tune"),
+		knob("Osc 1 Level", models.UnitPercent, 0, 100, "Osc 1 Volume", "Osc 1 Gain"),
+		knob("Osc 1 Unison Voices", "", 1, 16, "Osc 1 Voices", "Unison Voices", "Unison", "Voices"),
+		knob("Osc 1 Unison Detune", models.UnitPercent, 0, 100, "Osc 1 Detune", "Unison Detune", "Detune"),
+		knob("Osc 2 Frame", "", 0, 256, "Osc 2 Position", "Osc 2 WT Pos", "Osc 2 Wave Frame"),
+		knob("Osc 2 Transpose", models.UnitSemitones, -48, 48, "Osc 2 Pitch", "Osc 2 Semi"),
+		knob("Osc 2 Tune", models.UnitCents, -100, 100, "Osc 2 Fine"),
+		knob("Osc 2 Level", models.UnitPercent, 0, 100, "Osc 2 Volume", "Osc 2 Gain"),
+		knob("Osc 2 Unison Voices", "", 1, 16, "Osc 2 Voices"),
+		knob("Osc 2 Unison Detune", models.UnitPercent, 0, 100, "Osc 2 Detune"),
+		knob("Osc 3 Frame", "", 0, 256, "Osc 3 Position", "Osc 3 WT Pos", "Osc 3 Wave Frame"),
+		knob("Osc 3 Transpose", models.UnitSemitones, -48, 48, "Osc 3 Pitch", "Osc 3 Semi"),
+		knob("Osc 3 Level", models.UnitPercent, 0, 100, "Osc 3 Volume", "Osc 3 Gain"),
+		knob("Filter 1 Cutoff", models.UnitHertz, 13, 20900, "Filter 1 Frequency", "Filter 1 Freq", "Filter Cutoff", "Filter Frequency", "Filter Freq", "Flt Freq", "Cutoff", "Frequency"),
+		knob("Filter 1 Resonance", models.UnitPercent, 0, 100, "Filter 1 Res", "Filter Resonance", "Filter Res", "Resonance", "Res"),
+		knob("Filter 1 Drive", models.UnitDecibels, 0, 20, "Filter Drive"),
+		knob("Filter 1 Mix", models.UnitPercent, 0, 100, "Filter Mix"),
+		knob("Filter 2 Cutoff", models.UnitHertz, 13, 20900, "Filter 2 Frequency", "Filter 2 Freq"),
+		knob("Filter 2 Resonance", models.UnitPercent, 0, 100, "Filter 2 Res"),
+		knob("Env 1 Attack", models.UnitMillis, 0, 32000, "Amp Attack", "Attack"),
+		knob("Env 1 Decay", models.UnitMillis, 0, 32000, "Amp Decay", "Decay"),
+		knob("Env 1 Sustain", models.UnitPercent, 0, 100, "Amp Sustain", "Sustain"),
+		knob("Env 1 Release", models.UnitMillis, 0, 32000, "Amp Release", "Release"),
+		knob("Env 2 Attack", models.UnitMillis, 0, 32000, "Filter Env Attack"),
+		knob("Env 2 Decay", models.UnitMillis, 0, 32000, "Filter Env Decay"),
+		knob("Env 2 Sustain", models.UnitPercent, 0, 100, "Filter Env Sustain"),
+		knob("Env 2 Release", models.UnitMillis, 0, 32000, "Filter Env Release"),
+		knob("Distortion Drive", models.UnitDecibels, -30, 30, "Dist Drive", "Distortion", "Drive"),
+		knob("Distortion Mix", models.UnitPercent, 0, 100, "Dist Mix"),
+		knob("Compressor Mix", models.UnitPercent, 0, 100, "Compressor", "OTT"),
+		knob("Chorus Mix", models.UnitPercent, 0, 100, "Chorus Dry/Wet", "Chorus"),
+		knob("Flanger Mix", models.UnitPercent, 0, 100, "Flanger Dry/Wet", "Flanger"),
+		knob("Phaser Mix", models.UnitPercent, 0, 100, "Phaser Dry/Wet", "Phaser"),
+		knob("Delay Mix", models.UnitPercent, 0, 100, "Delay Dry/Wet", "Delay"),
+		knob("Reverb Mix", models.UnitPercent, 0, 100, "Reverb Dry/Wet", "Reverb"),
+		knob("Reverb Size", models.UnitPercent, 0, 100, "Room Size"),
+	},
+}
+
+// knob is a continuous control with a range
+func knob(name, unit string, min, max float64, aliases ...string) models.DeviceParameter {
+	return models.DeviceParameter{
+		Name:    name,
+		Aliases: aliases,
+		Unit:    unit,
+		Min:     &min,
+		Max:     &max,
+	}
+}
+
+// choice is a switch or menu, which has no numeric range
+func choice(name string, aliases ...string) models.DeviceParameter {
+	return models.DeviceParameter{
+		Name:    name,
+		Aliases: aliases,
+	}
+}
@@ -1,185 +1,63 @@
 package database
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"time"
+	"context"
+	"errors"
 
 	"github.com/camwick/sdr-backend/internal/models"
 )
 
-// Service handles database operations via Supabase REST API
-type Service struct {
-	baseURL string
-	apiKey  string
-	client  *http.Client
-}
-
-// NewService creates a new database service
-func NewService(supabaseURL, anonKey string) *Service {
-	return &Service{
-		baseURL: supabaseURL + "/rest/v1",
-		apiKey:  anonKey,
-		client:  &http.Client{},
-	}
-}
-
-// request helper for Supabase REST API
-func (s *Service) request(method, endpoint string, body interface{}, result interface{}) error {
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("failed to marshal body: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonBody)
-	}
-
-	req, err := http.NewRequest(method, s.baseURL+endpoint, reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("apikey", s.apiKey)
-	req.Header.Set("Authorization", "Bearer "+s.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("supabase error (status %d): %s", resp.StatusCode, string(respBody))
-	}
+var (
+	// ErrNotFound is returned when a requested tutorial does not exist
+	ErrNotFound = errors.New("tutorial not found")
 
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("failed to parse response: %w", err)
-		}
-	}
+	// ErrDuplicate is returned by SaveTutorial when the video has already been saved
+	ErrDuplicate = errors.New("tutorial already exists")
 
-	return nil
-}
-
-// GetOrCreateCreator finds a creator by handle or creates a new one
-func (s *Service) GetOrCreateCreator(handle, displayName string) (*models.Creator, error) {
-	// Try to find existing creator
-	var creators []models.Creator
-	endpoint := fmt.Sprintf("/creators?tiktok_handle=eq.%s&select=*", handle)
-	
-	if err := s.request("GET", endpoint, nil, &creators); err != nil {
-		return nil, err
-	}
-
-	if len(creators) > 0 {
-		return &creators[0], nil
-	}
-
-	// Create new creator
-	newCreator := map[string]interface{}{
-		"tiktok_handle": handle,
-		"display_name":  displayName,
-		"is_claimed":    false,
-		"created_at":    time.Now().UTC(),
-	}
-
-	var created []models.Creator
-	if err := s.request("POST", "/creators", newCreator, &created); err != nil {
-		return nil, fmt.Errorf("failed to create creator: %w", err)
-	}
-
-	if len(created) == 0 {
-		return nil, fmt.Errorf("no creator returned after insert")
-	}
-
-	return &created[0], nil
-}
-
-// GetTutorialByVideoID checks if a tutorial already exists
-func (s *Service) GetTutorialByVideoID(videoID string) (*models.Tutorial, error) {
-	var tutorials []models.Tutorial
-	endpoint := fmt.Sprintf("/tutorials?tiktok_video_id=eq.%s&select=*", videoID)
-	
-	if err := s.request("GET", endpoint, nil, &tutorials); err != nil {
-		return nil, err
-	}
-
-	if len(tutorials) > 0 {
-		return &tutorials[0], nil
-	}
-
-	return nil, nil
-}
-
-// CreateTutorial saves a new tutorial
-func (s *Service) CreateTutorial(tutorial *models.Tutorial) (*models.Tutorial, error) {
-	newTutorial := map[string]interface{}{
-		"creator_id":        tutorial.CreatorID,
-		"tiktok_url":        tutorial.TiktokURL,
-		"tiktok_video_id":   tutorial.TiktokVideoID,
-		"title":             tutorial.Title,
-		"sound_type":        tutorial.SoundType,
-		"raw_transcription": tutorial.RawTranscription,
-		"status":            "pending",
-		"created_at":        time.Now().UTC(),
-		"updated_at":        time.Now().UTC(),
-	}
-
-	var created []models.Tutorial
-	if err := s.request("POST", "/tutorials", newTutorial, &created); err != nil {
-		return nil, fmt.Errorf("failed to create tutorial: %w", err)
-	}
-
-	if len(created) == 0 {
-		return nil, fmt.Errorf("no tutorial returned after insert")
-	}
-
-	return &created[0], nil
-}
-
-// CreateInstructions saves instructions for a tutorial
-func (s *Service) CreateInstructions(tutorialID string, instructions []models.ParsedInstruction) error {
-	for _, inst := range instructions {
-		newInst := map[string]interface{}{
-			"tutorial_id":    tutorialID,
-			"step_number":    inst.StepNumber,
-			"description":    inst.Description,
-			"ableton_device": inst.AbletonDevice,
-			"parameters":     inst.Parameters,
-			"notes":          inst.Notes,
-		}
-
-		if err := s.request("POST", "/instructions", newInst, nil); err != nil {
-			return fmt.Errorf("failed to create instruction %d: %w", inst.StepNumber, err)
-		}
-	}
-
-	return nil
-}
-
-// GetTutorialWithInstructions fetches a tutorial with all its instructions
-func (s *Service) GetTutorialWithInstructions(tutorialID string) (*models.Tutorial, error) {
-	var tutorials []models.Tutorial
-	endpoint := fmt.Sprintf("/tutorials?id=eq.%s&select=*,creator:creators(*),instructions(*)", tutorialID)
-	
-	if err := s.request("GET", endpoint, nil, &tutorials); err != nil {
-		return nil, err
-	}
-
-	if len(tutorials) == 0 {
-		return nil, fmt.Errorf("tutorial not found")
-	}
+	// ErrTransient wraps failures that may succeed if retried, such as
+	// dropped connections, lock timeouts and serialization conflicts
+	ErrTransient = errors.New("transient database error")
+)
 
-	return &tutorials[0], nil
+// ListOptions filters and paginates tutorial listings
+type ListOptions struct {
+	Status        string // pending, approved, rejected; empty means any
+	Platform      string // tiktok, youtube, instagram, upload; empty means any
+	CreatorHandle string // empty means any creator
+	SoundType     string // empty means any sound type
+	Limit         int
+	Offset        int
+}
+
+// Repository is the storage layer for creators, tutorials and instructions.
+// Tutorials returned by GetTutorialWithInstructions, ListTutorials and
+// SearchTutorials have their Creator and Instructions populated, with
+// instructions ordered by step number.
+type Repository interface {
+	// GetOrCreateCreator finds a creator by platform and handle or creates a
+	// new one
+	GetOrCreateCreator(ctx context.Context, platform, handle, displayName string) (*models.Creator, error)
+
+	// GetTutorialByVideoID looks a tutorial up by platform and video ID,
+	// whatever its status. It returns nil, nil if the video hasn't been
+	// transcribed. Only the ID is guaranteed to be filled in.
+	GetTutorialByVideoID(ctx context.Context, platform, videoID string) (*models.Tutorial, error)
+
+	// GetTutorialWithInstructions returns ErrNotFound for unknown IDs
+	GetTutorialWithInstructions(ctx context.Context, tutorialID string) (*models.Tutorial, error)
+
+	// ListTutorials returns tutorials newest first
+	ListTutorials(ctx context.Context, opts ListOptions) ([]models.Tutorial, error)
+
+	// SearchTutorials matches the query against titles, sound types and transcriptions
+	SearchTutorials(ctx context.Context, query string, opts ListOptions) ([]models.Tutorial, error)
+
+	// CountRecipesByDevice returns how many tutorials with the given status
+	// (any if empty) use each AbletonDevice value in their instructions
+	CountRecipesByDevice(ctx context.Context, status string) (map[string]int, error)
+
+	// SaveTutorial atomically saves a new pending tutorial together with its
+	// Instructions and returns the stored tutorial. Either everything is
+	// persisted or nothing is, so the call can safely be retried.
+	SaveTutorial(ctx context.Context, tutorial *models.Tutorial) (*models.Tutorial, error)
 }
@@ -0,0 +1,389 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/database"
+)
+
+const creatorColumns = `c.id::text, c.platform, c.handle, c.display_name, COALESCE(c.avatar_url, ''),
+	COALESCE(c.is_claimed, false), c.created_at`
+
+const tutorialColumns = `t.id::text, t.creator_id::text, t.platform, t.source_url, t.video_id, t.title,
+	t.sound_type, t.raw_transcription, COALESCE(t.status, 'pending'), t.created_at, t.updated_at`
+
+const instructionColumns = `i.id::text, i.tutorial_id::text, i.step_number, i.description,
+	COALESCE(i.ableton_device, ''), COALESCE(i.parameters, '{}'), COALESCE(i.notes, ''),
+	COALESCE(i.screenshot_url, ''), i.start_time, i.end_time`
+
+const segmentColumns = `s.id::text, s.tutorial_id::text, s.segment_index, s.start_time, s.end_time, s.text`
+
+// Service talks to the tables in schema.sql over a direct PostgreSQL connection
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+var _ database.Repository = (*Service)(nil)
+
+// NewService connects to the database at databaseURL
+func NewService(ctx context.Context, databaseURL string) (*Service, error) {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection pool: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	return &Service{pool: pool}, nil
+}
+
+// OpenDB opens a database/sql handle, used for running migrations
+func OpenDB(databaseURL string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres database: %w", err)
+	}
+	return db, nil
+}
+
+// Close releases all pooled connections
+func (s *Service) Close() {
+	s.pool.Close()
+}
+
+// GetOrCreateCreator finds a creator by platform and handle or creates a new one
+func (s *Service) GetOrCreateCreator(ctx context.Context, platform, handle, displayName string) (*models.Creator, error) {
+	// The no-op update makes RETURNING yield the existing row on conflict
+	row := s.pool.QueryRow(ctx, `
+		INSERT INTO creators AS c (platform, handle, display_name, is_claimed)
+		VALUES ($1, $2, $3, false)
+		ON CONFLICT (platform, handle) DO UPDATE SET handle = EXCLUDED.handle
+		RETURNING `+creatorColumns,
+		platform, handle, displayName)
+
+	creator, err := scanCreator(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get or create creator: %w", err)
+	}
+
+	return creator, nil
+}
+
+// GetTutorialByVideoID checks if a tutorial already exists
+func (s *Service) GetTutorialByVideoID(ctx context.Context, platform, videoID string) (*models.Tutorial, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+tutorialColumns+` FROM tutorials t WHERE t.platform = $1 AND t.video_id = $2`,
+		platform, videoID)
+
+	tutorial, err := scanTutorial(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tutorial by video ID: %w", err)
+	}
+
+	return tutorial, nil
+}
+
+// GetTutorialWithInstructions fetches a tutorial with its creator, instructions
+// and transcript segments
+func (s *Service) GetTutorialWithInstructions(ctx context.Context, tutorialID string) (*models.Tutorial, error) {
+	tutorials, err := s.queryTutorials(ctx, `WHERE t.id = $1::uuid`, tutorialID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tutorials) == 0 {
+		return nil, database.ErrNotFound
+	}
+
+	tutorial := &tutorials[0]
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+segmentColumns+`
+		FROM transcript_segments s
+		WHERE s.tutorial_id = $1::uuid
+		ORDER BY s.segment_index`, tutorialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transcript segments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var seg models.TranscriptSegment
+		if err := rows.Scan(&seg.ID, &seg.TutorialID, &seg.SegmentIndex, &seg.StartTime, &seg.EndTime, &seg.Text); err != nil {
+			return nil, fmt.Errorf("failed to scan transcript segment: %w", err)
+		}
+		tutorial.Segments = append(tutorial.Segments, seg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript segments: %w", err)
+	}
+
+	return tutorial, nil
+}
+
+// listFilters applies ListOptions, with the status, creator handle, sound
+// type and platform as $1, $4, $5 and $6
+const listFilters = `($1 = '' OR t.status = $1)
+		AND ($4 = '' OR c.handle = $4)
+		AND ($5 = '' OR t.sound_type = $5)
+		AND ($6 = '' OR t.platform = $6)`
+
+// ListTutorials fetches tutorials with their creator and instructions, newest first
+func (s *Service) ListTutorials(ctx context.Context, opts database.ListOptions) ([]models.Tutorial, error) {
+	return s.queryTutorials(ctx, `
+		WHERE `+listFilters+`
+		ORDER BY t.created_at DESC
+		LIMIT $2 OFFSET $3`,
+		opts.Status, limitArg(opts.Limit), opts.Offset, opts.CreatorHandle, opts.SoundType, opts.Platform)
+}
+
+// SearchTutorials matches the query against tutorial titles, sound types and transcriptions
+func (s *Service) SearchTutorials(ctx context.Context, query string, opts database.ListOptions) ([]models.Tutorial, error) {
+	return s.queryTutorials(ctx, `
+		WHERE `+listFilters+`
+		AND (t.title ILIKE $7 OR t.sound_type ILIKE $7 OR t.raw_transcription ILIKE $7)
+		ORDER BY t.created_at DESC
+		LIMIT $2 OFFSET $3`,
+		opts.Status, limitArg(opts.Limit), opts.Offset, opts.CreatorHandle, opts.SoundType, opts.Platform, "%"+escapeLike(query)+"%")
+}
+
+// CountRecipesByDevice counts tutorials per device named in their instructions
+func (s *Service) CountRecipesByDevice(ctx context.Context, status string) (map[string]int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT i.ableton_device, COUNT(DISTINCT i.tutorial_id)
+		FROM instructions i
+		JOIN tutorials t ON t.id = i.tutorial_id
+		WHERE ($1 = '' OR t.status = $1)
+		AND COALESCE(i.ableton_device, '') <> ''
+		GROUP BY i.ableton_device`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recipes by device: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var device string
+		var count int
+		if err := rows.Scan(&device, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan device count: %w", err)
+		}
+		counts[device] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read device counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// SaveTutorial inserts the tutorial, its instructions and its transcript
+// segments in one transaction
+func (s *Service) SaveTutorial(ctx context.Context, tutorial *models.Tutorial) (*models.Tutorial, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", classifyError(ctx, err))
+	}
+	defer tx.Rollback(ctx)
+
+	row := tx.QueryRow(ctx, `
+		INSERT INTO tutorials AS t (creator_id, platform, source_url, video_id, title, sound_type, raw_transcription, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'pending')
+		RETURNING `+tutorialColumns,
+		tutorial.CreatorID, tutorial.Platform, tutorial.SourceURL, tutorial.VideoID,
+		tutorial.Title, tutorial.SoundType, tutorial.RawTranscription)
+
+	saved, err := scanTutorial(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tutorial: %w", classifyError(ctx, err))
+	}
+
+	rows := make([][]interface{}, 0, len(tutorial.Instructions))
+	for _, inst := range tutorial.Instructions {
+		params := inst.Parameters
+		if params == nil {
+			params = map[string]models.ParameterValue{}
+		}
+		rows = append(rows, []interface{}{
+			saved.ID, inst.StepNumber, inst.Description, inst.AbletonDevice, params, inst.Notes,
+			inst.StartTime, inst.EndTime,
+		})
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"instructions"},
+		[]string{"tutorial_id", "step_number", "description", "ableton_device", "parameters", "notes", "start_time", "end_time"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instructions: %w", classifyError(ctx, err))
+	}
+
+	segmentRows := make([][]interface{}, 0, len(tutorial.Segments))
+	for _, seg := range tutorial.Segments {
+		segmentRows = append(segmentRows, []interface{}{
+			saved.ID, seg.SegmentIndex, seg.StartTime, seg.EndTime, seg.Text,
+		})
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"transcript_segments"},
+		[]string{"tutorial_id", "segment_index", "start_time", "end_time", "text"},
+		pgx.CopyFromRows(segmentRows))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript segments: %w", classifyError(ctx, err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit tutorial: %w", classifyError(ctx, err))
+	}
+
+	return saved, nil
+}
+
+// queryTutorials runs a tutorial query joined with creators and attaches instructions
+func (s *Service) queryTutorials(ctx context.Context, clause string, args ...interface{}) ([]models.Tutorial, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+tutorialColumns+`, `+creatorColumns+`
+		FROM tutorials t
+		JOIN creators c ON c.id = t.creator_id
+		`+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tutorials: %w", err)
+	}
+	defer rows.Close()
+
+	tutorials := []models.Tutorial{}
+	index := map[string]int{}
+	for rows.Next() {
+		var t models.Tutorial
+		var c models.Creator
+		if err := rows.Scan(
+			&t.ID, &t.CreatorID, &t.Platform, &t.SourceURL, &t.VideoID, &t.Title,
+			&t.SoundType, &t.RawTranscription, &t.Status, &t.CreatedAt, &t.UpdatedAt,
+			&c.ID, &c.Platform, &c.Handle, &c.DisplayName, &c.AvatarURL, &c.IsClaimed, &c.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan tutorial: %w", err)
+		}
+		t.Creator = &c
+		index[t.ID] = len(tutorials)
+		tutorials = append(tutorials, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tutorials: %w", err)
+	}
+
+	if len(tutorials) == 0 {
+		return tutorials, nil
+	}
+
+	ids := make([]string, 0, len(tutorials))
+	for _, t := range tutorials {
+		ids = append(ids, t.ID)
+	}
+
+	instRows, err := s.pool.Query(ctx, `
+		SELECT `+instructionColumns+`
+		FROM instructions i
+		WHERE i.tutorial_id = ANY($1::text[]::uuid[])
+		ORDER BY i.tutorial_id, i.step_number`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query instructions: %w", err)
+	}
+	defer instRows.Close()
+
+	for instRows.Next() {
+		var inst models.Instruction
+		if err := instRows.Scan(
+			&inst.ID, &inst.TutorialID, &inst.StepNumber, &inst.Description,
+			&inst.AbletonDevice, &inst.Parameters, &inst.Notes, &inst.ScreenshotURL,
+			&inst.StartTime, &inst.EndTime,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan instruction: %w", err)
+		}
+		t := &tutorials[index[inst.TutorialID]]
+		t.Instructions = append(t.Instructions, inst)
+	}
+	if err := instRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read instructions: %w", err)
+	}
+
+	return tutorials, nil
+}
+
+func scanCreator(row pgx.Row) (*models.Creator, error) {
+	var c models.Creator
+	if err := row.Scan(&c.ID, &c.Platform, &c.Handle, &c.DisplayName, &c.AvatarURL, &c.IsClaimed, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanTutorial(row pgx.Row) (*models.Tutorial, error) {
+	var t models.Tutorial
+	if err := row.Scan(
+		&t.ID, &t.CreatorID, &t.Platform, &t.SourceURL, &t.VideoID, &t.Title,
+		&t.SoundType, &t.RawTranscription, &t.Status, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// classifyError marks unique violations as database.ErrDuplicate, and
+// serialization failures, deadlocks and lost connections as database.ErrTransient
+func classifyError(ctx context.Context, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23505":
+			return fmt.Errorf("%w: %w", database.ErrDuplicate, err)
+		case pgErr.Code == "40001", pgErr.Code == "40P01", pgErr.Code == "57P01",
+			strings.HasPrefix(pgErr.Code, "08"):
+			return fmt.Errorf("%w: %w", database.ErrTransient, err)
+		}
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return err
+	}
+
+	// Only connection failures are worth retrying; scan and encoding errors
+	// would fail the same way again
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if pgconn.SafeToRetry(err) || errors.As(err, &connectErr) || errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", database.ErrTransient, err)
+	}
+	return err
+}
+
+// limitArg maps a zero limit to NULL, which Postgres treats as no limit
+func limitArg(limit int) interface{} {
+	if limit <= 0 {
+		return nil
+	}
+	return limit
+}
+
+// escapeLike escapes ILIKE wildcards so the query is matched literally
+func escapeLike(query string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+}
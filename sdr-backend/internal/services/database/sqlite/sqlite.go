@@ -0,0 +1,434 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/camwick/sdr-backend/internal/migrations"
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/database"
+)
+
+const creatorColumns = `c.id, c.platform, c.handle, c.display_name, COALESCE(c.avatar_url, ''),
+	c.is_claimed, c.created_at`
+
+const tutorialColumns = `t.id, t.creator_id, t.platform, t.source_url, t.video_id, t.title,
+	t.sound_type, t.raw_transcription, t.status, t.created_at, t.updated_at`
+
+const instructionColumns = `i.id, i.tutorial_id, i.step_number, i.description,
+	COALESCE(i.ableton_device, ''), i.parameters, COALESCE(i.notes, ''),
+	COALESCE(i.screenshot_url, ''), i.start_time, i.end_time`
+
+const segmentColumns = `s.id, s.tutorial_id, s.segment_index, s.start_time, s.end_time, s.text`
+
+// Service stores creators, tutorials and instructions in a local SQLite file
+type Service struct {
+	db *sql.DB
+}
+
+var _ database.Repository = (*Service)(nil)
+
+// NewService opens (or creates) the database file at path and applies any
+// pending migrations, so a fresh file is ready to use
+func NewService(ctx context.Context, path string) (*Service, error) {
+	db, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migrations.New(db, migrations.SQLite)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	for _, migration := range applied {
+		log.Printf("Applied sqlite migration %04d_%s", migration.Version, migration.Name)
+	}
+
+	return &Service{db: db}, nil
+}
+
+// Open opens the database file at path with foreign keys enforced and
+// write transactions taking the lock up front
+func Open(path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	return db, nil
+}
+
+// Close closes the underlying database
+func (s *Service) Close() error {
+	return s.db.Close()
+}
+
+// GetOrCreateCreator finds a creator by platform and handle or creates a new one
+func (s *Service) GetOrCreateCreator(ctx context.Context, platform, handle, displayName string) (*models.Creator, error) {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO creators (id, platform, handle, display_name, is_claimed, created_at)
+		VALUES (?, ?, ?, ?, FALSE, ?)
+		ON CONFLICT (platform, handle) DO NOTHING`,
+		newUUID(), platform, handle, displayName, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create creator: %w", err)
+	}
+
+	var c models.Creator
+	err = s.db.QueryRowContext(ctx, `SELECT `+creatorColumns+` FROM creators c WHERE c.platform = ? AND c.handle = ?`, platform, handle).
+		Scan(&c.ID, &c.Platform, &c.Handle, &c.DisplayName, &c.AvatarURL, &c.IsClaimed, &c.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get creator: %w", err)
+	}
+
+	return &c, nil
+}
+
+// GetTutorialByVideoID checks if a tutorial already exists
+func (s *Service) GetTutorialByVideoID(ctx context.Context, platform, videoID string) (*models.Tutorial, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+tutorialColumns+` FROM tutorials t WHERE t.platform = ? AND t.video_id = ?`,
+		platform, videoID)
+
+	tutorial, err := scanTutorial(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tutorial by video ID: %w", err)
+	}
+
+	return tutorial, nil
+}
+
+// GetTutorialWithInstructions fetches a tutorial with its creator, instructions
+// and transcript segments
+func (s *Service) GetTutorialWithInstructions(ctx context.Context, tutorialID string) (*models.Tutorial, error) {
+	tutorials, err := s.queryTutorials(ctx, `WHERE t.id = ?`, tutorialID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tutorials) == 0 {
+		return nil, database.ErrNotFound
+	}
+
+	tutorial := &tutorials[0]
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+segmentColumns+`
+		FROM transcript_segments s
+		WHERE s.tutorial_id = ?
+		ORDER BY s.segment_index`, tutorialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transcript segments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var seg models.TranscriptSegment
+		if err := rows.Scan(&seg.ID, &seg.TutorialID, &seg.SegmentIndex, &seg.StartTime, &seg.EndTime, &seg.Text); err != nil {
+			return nil, fmt.Errorf("failed to scan transcript segment: %w", err)
+		}
+		tutorial.Segments = append(tutorial.Segments, seg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript segments: %w", err)
+	}
+
+	return tutorial, nil
+}
+
+// listFilters applies ListOptions, with the status, creator handle, sound
+// type and platform as ?1, ?4, ?5 and ?6
+const listFilters = `(?1 = '' OR t.status = ?1)
+		AND (?4 = '' OR c.handle = ?4)
+		AND (?5 = '' OR t.sound_type = ?5)
+		AND (?6 = '' OR t.platform = ?6)`
+
+// ListTutorials fetches tutorials with their creator and instructions, newest first
+func (s *Service) ListTutorials(ctx context.Context, opts database.ListOptions) ([]models.Tutorial, error) {
+	return s.queryTutorials(ctx, `
+		WHERE `+listFilters+`
+		ORDER BY t.created_at DESC
+		LIMIT ?2 OFFSET ?3`,
+		opts.Status, limitArg(opts.Limit), opts.Offset, opts.CreatorHandle, opts.SoundType, opts.Platform)
+}
+
+// SearchTutorials matches the query against tutorial titles, sound types and transcriptions
+func (s *Service) SearchTutorials(ctx context.Context, query string, opts database.ListOptions) ([]models.Tutorial, error) {
+	return s.queryTutorials(ctx, `
+		WHERE `+listFilters+`
+		AND (t.title LIKE ?7 ESCAPE '\' OR t.sound_type LIKE ?7 ESCAPE '\' OR t.raw_transcription LIKE ?7 ESCAPE '\')
+		ORDER BY t.created_at DESC
+		LIMIT ?2 OFFSET ?3`,
+		opts.Status, limitArg(opts.Limit), opts.Offset, opts.CreatorHandle, opts.SoundType, opts.Platform, "%"+escapeLike(query)+"%")
+}
+
+// CountRecipesByDevice counts tutorials per device named in their instructions
+func (s *Service) CountRecipesByDevice(ctx context.Context, status string) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT i.ableton_device, COUNT(DISTINCT i.tutorial_id)
+		FROM instructions i
+		JOIN tutorials t ON t.id = i.tutorial_id
+		WHERE (?1 = '' OR t.status = ?1)
+		AND COALESCE(i.ableton_device, '') <> ''
+		GROUP BY i.ableton_device`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recipes by device: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var device string
+		var count int
+		if err := rows.Scan(&device, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan device count: %w", err)
+		}
+		counts[device] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read device counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// SaveTutorial inserts the tutorial, its instructions and its transcript
+// segments in one transaction
+func (s *Service) SaveTutorial(ctx context.Context, tutorial *models.Tutorial) (*models.Tutorial, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", classifyError(err))
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	saved := &models.Tutorial{
+		ID:               newUUID(),
+		CreatorID:        tutorial.CreatorID,
+		Platform:         tutorial.Platform,
+		SourceURL:        tutorial.SourceURL,
+		VideoID:          tutorial.VideoID,
+		Title:            tutorial.Title,
+		SoundType:        tutorial.SoundType,
+		RawTranscription: tutorial.RawTranscription,
+		Status:           "pending",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tutorials (id, creator_id, platform, source_url, video_id, title, sound_type, raw_transcription, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		saved.ID, saved.CreatorID, saved.Platform, saved.SourceURL, saved.VideoID, saved.Title,
+		saved.SoundType, saved.RawTranscription, saved.Status, saved.CreatedAt, saved.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tutorial: %w", classifyError(err))
+	}
+
+	if len(tutorial.Instructions) > 0 {
+		placeholders := make([]string, 0, len(tutorial.Instructions))
+		args := make([]interface{}, 0, len(tutorial.Instructions)*9)
+		for _, inst := range tutorial.Instructions {
+			params, err := marshalParameters(inst.Parameters)
+			if err != nil {
+				return nil, err
+			}
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			args = append(args, newUUID(), saved.ID, inst.StepNumber, inst.Description, inst.AbletonDevice, params, inst.Notes,
+				inst.StartTime, inst.EndTime)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO instructions (id, tutorial_id, step_number, description, ableton_device, parameters, notes, start_time, end_time)
+			VALUES `+strings.Join(placeholders, ", "), args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create instructions: %w", classifyError(err))
+		}
+	}
+
+	if len(tutorial.Segments) > 0 {
+		placeholders := make([]string, 0, len(tutorial.Segments))
+		args := make([]interface{}, 0, len(tutorial.Segments)*6)
+		for _, seg := range tutorial.Segments {
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?)")
+			args = append(args, newUUID(), saved.ID, seg.SegmentIndex, seg.StartTime, seg.EndTime, seg.Text)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO transcript_segments (id, tutorial_id, segment_index, start_time, end_time, text)
+			VALUES `+strings.Join(placeholders, ", "), args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transcript segments: %w", classifyError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tutorial: %w", classifyError(err))
+	}
+
+	return saved, nil
+}
+
+// queryTutorials runs a tutorial query joined with creators and attaches instructions
+func (s *Service) queryTutorials(ctx context.Context, clause string, args ...interface{}) ([]models.Tutorial, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+tutorialColumns+`, `+creatorColumns+`
+		FROM tutorials t
+		JOIN creators c ON c.id = t.creator_id
+		`+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tutorials: %w", err)
+	}
+	defer rows.Close()
+
+	tutorials := []models.Tutorial{}
+	index := map[string]int{}
+	for rows.Next() {
+		var t models.Tutorial
+		var c models.Creator
+		if err := rows.Scan(
+			&t.ID, &t.CreatorID, &t.Platform, &t.SourceURL, &t.VideoID, &t.Title,
+			&t.SoundType, &t.RawTranscription, &t.Status, &t.CreatedAt, &t.UpdatedAt,
+			&c.ID, &c.Platform, &c.Handle, &c.DisplayName, &c.AvatarURL, &c.IsClaimed, &c.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan tutorial: %w", err)
+		}
+		t.Creator = &c
+		index[t.ID] = len(tutorials)
+		tutorials = append(tutorials, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tutorials: %w", err)
+	}
+	rows.Close()
+
+	if len(tutorials) == 0 {
+		return tutorials, nil
+	}
+
+	placeholders := make([]string, 0, len(tutorials))
+	ids := make([]interface{}, 0, len(tutorials))
+	for _, t := range tutorials {
+		placeholders = append(placeholders, "?")
+		ids = append(ids, t.ID)
+	}
+
+	instRows, err := s.db.QueryContext(ctx, `
+		SELECT `+instructionColumns+`
+		FROM instructions i
+		WHERE i.tutorial_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY i.tutorial_id, i.step_number`, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query instructions: %w", err)
+	}
+	defer instRows.Close()
+
+	for instRows.Next() {
+		var inst models.Instruction
+		var params string
+		if err := instRows.Scan(
+			&inst.ID, &inst.TutorialID, &inst.StepNumber, &inst.Description,
+			&inst.AbletonDevice, &params, &inst.Notes, &inst.ScreenshotURL,
+			&inst.StartTime, &inst.EndTime,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan instruction: %w", err)
+		}
+		if err := json.Unmarshal([]byte(params), &inst.Parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse parameters for instruction %s: %w", inst.ID, err)
+		}
+		t := &tutorials[index[inst.TutorialID]]
+		t.Instructions = append(t.Instructions, inst)
+	}
+	if err := instRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read instructions: %w", err)
+	}
+
+	return tutorials, nil
+}
+
+func scanTutorial(row *sql.Row) (*models.Tutorial, error) {
+	var t models.Tutorial
+	if err := row.Scan(
+		&t.ID, &t.CreatorID, &t.Platform, &t.SourceURL, &t.VideoID, &t.Title,
+		&t.SoundType, &t.RawTranscription, &t.Status, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// classifyError marks unique violations as database.ErrDuplicate and lock
+// contention as database.ErrTransient
+func classifyError(err error) error {
+	var sqliteErr *sqlitedriver.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch {
+	case sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		return fmt.Errorf("%w: %w", database.ErrDuplicate, err)
+	case sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY, sqliteErr.Code()&0xff == sqlite3.SQLITE_LOCKED:
+		return fmt.Errorf("%w: %w", database.ErrTransient, err)
+	default:
+		return err
+	}
+}
+
+func marshalParameters(params map[string]models.ParameterValue) (string, error) {
+	if params == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal parameters: %w", err)
+	}
+	return string(data), nil
+}
+
+// limitArg maps a zero limit to -1, which SQLite treats as no limit
+func limitArg(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+// escapeLike escapes LIKE wildcards so the query is matched literally
+func escapeLike(query string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+}
+
+// newUUID returns a random version 4 UUID, matching uuid_generate_v4 in Postgres
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,223 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/database"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	s, err := NewService(context.Background(), filepath.Join(t.TempDir(), "sdr.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func saveTestTutorial(t *testing.T, s *Service, creator *models.Creator, videoID, soundType string) *models.Tutorial {
+	t.Helper()
+
+	start := 1.5
+	saved, err := s.SaveTutorial(context.Background(), &models.Tutorial{
+		CreatorID:        creator.ID,
+		Platform:         creator.Platform,
+		SourceURL:        "https://example.com/" + videoID,
+		VideoID:          videoID,
+		Title:            "Recipe " + videoID,
+		SoundType:        soundType,
+		RawTranscription: "load wavetable and set the cutoff",
+		Instructions: []models.Instruction{
+			{StepNumber: 2, Description: "Set the cutoff", AbletonDevice: "Auto Filter"},
+			{StepNumber: 1, Description: "Load Wavetable", AbletonDevice: "Wavetable", StartTime: &start},
+		},
+		Segments: []models.TranscriptSegment{
+			{SegmentIndex: 0, StartTime: 0, EndTime: 4, Text: "load wavetable"},
+			{SegmentIndex: 1, StartTime: 4, EndTime: 8, Text: "and set the cutoff"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return saved
+}
+
+func TestGetOrCreateCreator(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	first, err := s.GetOrCreateCreator(ctx, "tiktok", "producer", "Producer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.GetOrCreateCreator(ctx, "tiktok", "producer", "Renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID || again.DisplayName != "Producer" {
+		t.Errorf("second call = %+v, want the existing creator %+v", again, first)
+	}
+
+	other, err := s.GetOrCreateCreator(ctx, "youtube", "producer", "Producer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID == first.ID || other.Platform != "youtube" {
+		t.Errorf("youtube creator = %+v, want a separate creator", other)
+	}
+}
+
+func TestSaveTutorial(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	creator, err := s.GetOrCreateCreator(ctx, "tiktok", "producer", "Producer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := saveTestTutorial(t, s, creator, "1", "bass")
+	if saved.ID == "" || saved.Status != "pending" {
+		t.Fatalf("saved = %+v", saved)
+	}
+
+	got, err := s.GetTutorialWithInstructions(ctx, saved.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Creator == nil || got.Creator.Handle != "producer" {
+		t.Errorf("Creator = %+v", got.Creator)
+	}
+	if len(got.Instructions) != 2 || got.Instructions[0].StepNumber != 1 || got.Instructions[1].StepNumber != 2 {
+		t.Errorf("Instructions = %+v, want steps 1 and 2 in order", got.Instructions)
+	}
+	if start := got.Instructions[0].StartTime; start == nil || *start != 1.5 {
+		t.Errorf("StartTime = %v, want 1.5", start)
+	}
+	if len(got.Segments) != 2 || got.Segments[1].Text != "and set the cutoff" {
+		t.Errorf("Segments = %+v", got.Segments)
+	}
+
+	existing, err := s.GetTutorialByVideoID(ctx, "tiktok", "1")
+	if err != nil || existing == nil || existing.ID != saved.ID {
+		t.Errorf("GetTutorialByVideoID = %+v, %v, want the saved tutorial", existing, err)
+	}
+	if missing, err := s.GetTutorialByVideoID(ctx, "youtube", "1"); err != nil || missing != nil {
+		t.Errorf("GetTutorialByVideoID on another platform = %+v, %v, want nil", missing, err)
+	}
+
+	if _, err := s.GetTutorialWithInstructions(ctx, "missing"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("unknown ID: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSaveTutorialDuplicate(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	creator, err := s.GetOrCreateCreator(ctx, "tiktok", "producer", "Producer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveTestTutorial(t, s, creator, "1", "bass")
+
+	_, err = s.SaveTutorial(ctx, &models.Tutorial{
+		CreatorID: creator.ID, Platform: "tiktok", VideoID: "1", Title: "Again", SoundType: "bass",
+		Instructions: []models.Instruction{{StepNumber: 1, Description: "Load Wavetable"}},
+	})
+	if !errors.Is(err, database.ErrDuplicate) {
+		t.Fatalf("err = %v, want ErrDuplicate", err)
+	}
+
+	// Nothing from the failed save is left behind
+	tutorials, err := s.ListTutorials(ctx, database.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tutorials) != 1 || tutorials[0].Title != "Recipe 1" {
+		t.Errorf("tutorials = %+v, want only the first save", tutorials)
+	}
+
+	// The same video ID on another platform is a different video
+	youtube, err := s.GetOrCreateCreator(ctx, "youtube", "producer", "Producer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveTestTutorial(t, s, youtube, "1", "bass")
+}
+
+func TestListTutorials(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	tiktok, err := s.GetOrCreateCreator(ctx, "tiktok", "producer", "Producer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	youtube, err := s.GetOrCreateCreator(ctx, "youtube", "other", "Other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bass := saveTestTutorial(t, s, tiktok, "1", "bass")
+	saveTestTutorial(t, s, tiktok, "2", "pad")
+	saveTestTutorial(t, s, youtube, "3", "bass")
+
+	if _, err := s.db.ExecContext(ctx, `UPDATE tutorials SET status = 'approved' WHERE id = ?`, bass.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts database.ListOptions
+		want []string // video IDs, newest first
+	}{
+		{"all", database.ListOptions{}, []string{"3", "2", "1"}},
+		{"status", database.ListOptions{Status: "approved"}, []string{"1"}},
+		{"platform", database.ListOptions{Platform: "youtube"}, []string{"3"}},
+		{"creator", database.ListOptions{CreatorHandle: "producer"}, []string{"2", "1"}},
+		{"sound type", database.ListOptions{SoundType: "bass"}, []string{"3", "1"}},
+		{"combined", database.ListOptions{SoundType: "bass", CreatorHandle: "producer"}, []string{"1"}},
+		{"limit", database.ListOptions{Limit: 2}, []string{"3", "2"}},
+		{"offset", database.ListOptions{Limit: 2, Offset: 2}, []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tutorials, err := s.ListTutorials(ctx, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, tutorial := range tutorials {
+				got = append(got, tutorial.VideoID)
+				if tutorial.Creator == nil || len(tutorial.Instructions) != 2 {
+					t.Errorf("tutorial %s has creator %+v and %d instructions", tutorial.VideoID, tutorial.Creator, len(tutorial.Instructions))
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	t.Run("search", func(t *testing.T) {
+		tutorials, err := s.SearchTutorials(ctx, "pad", database.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tutorials) != 1 || tutorials[0].VideoID != "2" {
+			t.Errorf("search = %+v, want video 2", tutorials)
+		}
+	})
+}
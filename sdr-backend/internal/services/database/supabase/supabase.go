@@ -0,0 +1,324 @@
+package supabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/database"
+)
+
+// tutorialSelect embeds the creator and instructions in tutorial queries
+const tutorialSelect = "*,creator:creators(*),instructions(*)"
+
+// tutorialDetailSelect also embeds the transcript segments, which are only
+// returned for single tutorials
+const tutorialDetailSelect = tutorialSelect + ",transcript_segments(*)"
+
+// Service handles database operations via Supabase REST API
+type Service struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+var _ database.Repository = (*Service)(nil)
+
+// NewService creates a new database service
+func NewService(supabaseURL, anonKey string) *Service {
+	return &Service{
+		baseURL: supabaseURL + "/rest/v1",
+		apiKey:  anonKey,
+		client:  &http.Client{},
+	}
+}
+
+// request helper for Supabase REST API
+func (s *Service) request(ctx context.Context, method, endpoint string, body interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal body: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+endpoint, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("apikey", s.apiKey)
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "return=representation")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+		return fmt.Errorf("%w: failed to send request: %w", database.ErrTransient, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read response: %w", database.ErrTransient, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return classifyError(resp.StatusCode, respBody)
+	}
+
+	if result != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// classifyError maps a PostgREST error response onto the database sentinel errors
+func classifyError(status int, body []byte) error {
+	err := fmt.Errorf("supabase error (status %d): %s", status, string(body))
+
+	var pgErr struct {
+		Code string `json:"code"`
+	}
+	json.Unmarshal(body, &pgErr)
+
+	switch {
+	case pgErr.Code == "23505":
+		return fmt.Errorf("%w: %w", database.ErrDuplicate, err)
+	case pgErr.Code == "40001", pgErr.Code == "40P01",
+		status == http.StatusTooManyRequests,
+		status == http.StatusBadGateway,
+		status == http.StatusServiceUnavailable,
+		status == http.StatusGatewayTimeout:
+		return fmt.Errorf("%w: %w", database.ErrTransient, err)
+	default:
+		return err
+	}
+}
+
+// GetOrCreateCreator finds a creator by platform and handle or creates a new one
+func (s *Service) GetOrCreateCreator(ctx context.Context, platform, handle, displayName string) (*models.Creator, error) {
+	creator, err := s.findCreator(ctx, platform, handle)
+	if err != nil || creator != nil {
+		return creator, err
+	}
+
+	// Create new creator
+	newCreator := map[string]interface{}{
+		"platform":     platform,
+		"handle":       handle,
+		"display_name": displayName,
+		"is_claimed":   false,
+		"created_at":   time.Now().UTC(),
+	}
+
+	var created []models.Creator
+	err = s.request(ctx, "POST", "/creators", newCreator, &created)
+	if errors.Is(err, database.ErrDuplicate) {
+		// Created by a concurrent job since the lookup
+		creator, err := s.findCreator(ctx, platform, handle)
+		if err == nil && creator == nil {
+			err = fmt.Errorf("creator missing after duplicate insert")
+		}
+		return creator, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create creator: %w", err)
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("no creator returned after insert")
+	}
+
+	return &created[0], nil
+}
+
+// findCreator returns nil, nil if there's no creator with the handle on the platform
+func (s *Service) findCreator(ctx context.Context, platform, handle string) (*models.Creator, error) {
+	params := url.Values{}
+	params.Set("platform", "eq."+platform)
+	params.Set("handle", "eq."+handle)
+	params.Set("select", "*")
+
+	var creators []models.Creator
+	if err := s.request(ctx, "GET", "/creators?"+params.Encode(), nil, &creators); err != nil {
+		return nil, err
+	}
+
+	if len(creators) == 0 {
+		return nil, nil
+	}
+
+	return &creators[0], nil
+}
+
+// GetTutorialByVideoID checks if a tutorial already exists. Row level
+// security hides pending tutorials from the anon key, so the lookup goes
+// through the tutorial_id_for_video Postgres function, and only the ID,
+// platform and video ID of the tutorial are filled in.
+func (s *Service) GetTutorialByVideoID(ctx context.Context, platform, videoID string) (*models.Tutorial, error) {
+	var id *string
+	body := map[string]interface{}{"p_platform": platform, "p_video_id": videoID}
+	if err := s.request(ctx, "POST", "/rpc/tutorial_id_for_video", body, &id); err != nil {
+		return nil, err
+	}
+
+	if id == nil {
+		return nil, nil
+	}
+
+	return &models.Tutorial{ID: *id, Platform: platform, VideoID: videoID}, nil
+}
+
+// CountRecipesByDevice counts tutorials per device through the
+// device_recipe_counts Postgres function, since PostgREST can't group. Like
+// the other queries it only sees the tutorials row level security allows.
+func (s *Service) CountRecipesByDevice(ctx context.Context, status string) (map[string]int, error) {
+	var rows []struct {
+		AbletonDevice string `json:"ableton_device"`
+		RecipeCount   int    `json:"recipe_count"`
+	}
+	body := map[string]interface{}{"p_status": status}
+	if err := s.request(ctx, "POST", "/rpc/device_recipe_counts", body, &rows); err != nil {
+		return nil, fmt.Errorf("failed to count recipes by device: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.AbletonDevice] = row.RecipeCount
+	}
+
+	return counts, nil
+}
+
+// SaveTutorial creates the tutorial, its instructions and its transcript
+// segments in one call to the
+// create_tutorial_with_instructions Postgres function, which runs in a
+// single transaction
+func (s *Service) SaveTutorial(ctx context.Context, tutorial *models.Tutorial) (*models.Tutorial, error) {
+	instructions := make([]map[string]interface{}, 0, len(tutorial.Instructions))
+	for _, inst := range tutorial.Instructions {
+		instructions = append(instructions, map[string]interface{}{
+			"step_number":    inst.StepNumber,
+			"description":    inst.Description,
+			"ableton_device": inst.AbletonDevice,
+			"parameters":     inst.Parameters,
+			"notes":          inst.Notes,
+			"start_time":     inst.StartTime,
+			"end_time":       inst.EndTime,
+		})
+	}
+
+	body := map[string]interface{}{
+		"p_tutorial": map[string]interface{}{
+			"creator_id":        tutorial.CreatorID,
+			"platform":          tutorial.Platform,
+			"source_url":        tutorial.SourceURL,
+			"video_id":          tutorial.VideoID,
+			"title":             tutorial.Title,
+			"sound_type":        tutorial.SoundType,
+			"raw_transcription": tutorial.RawTranscription,
+		},
+		"p_instructions": instructions,
+		"p_segments":     tutorial.Segments,
+	}
+
+	var created models.Tutorial
+	if err := s.request(ctx, "POST", "/rpc/create_tutorial_with_instructions", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create tutorial: %w", err)
+	}
+
+	if created.ID == "" {
+		return nil, fmt.Errorf("no tutorial returned after insert")
+	}
+
+	return &created, nil
+}
+
+// GetTutorialWithInstructions fetches a tutorial with all its instructions
+// and transcript segments
+func (s *Service) GetTutorialWithInstructions(ctx context.Context, tutorialID string) (*models.Tutorial, error) {
+	var tutorials []models.Tutorial
+	endpoint := fmt.Sprintf("/tutorials?id=eq.%s&select=%s&instructions.order=step_number.asc&transcript_segments.order=segment_index.asc", tutorialID, tutorialDetailSelect)
+	
+	if err := s.request(ctx, "GET", endpoint, nil, &tutorials); err != nil {
+		return nil, err
+	}
+
+	if len(tutorials) == 0 {
+		return nil, database.ErrNotFound
+	}
+
+	return &tutorials[0], nil
+}
+
+// ListTutorials fetches tutorials with their creator and instructions, newest first
+func (s *Service) ListTutorials(ctx context.Context, opts database.ListOptions) ([]models.Tutorial, error) {
+	return s.listTutorials(ctx, url.Values{}, opts)
+}
+
+// SearchTutorials matches the query against tutorial titles, sound types and transcriptions
+func (s *Service) SearchTutorials(ctx context.Context, query string, opts database.ListOptions) ([]models.Tutorial, error) {
+	pattern := quoteFilterValue("*" + query + "*")
+	params := url.Values{}
+	params.Set("or", fmt.Sprintf("(title.ilike.%s,sound_type.ilike.%s,raw_transcription.ilike.%s)",
+		pattern, pattern, pattern))
+	return s.listTutorials(ctx, params, opts)
+}
+
+func (s *Service) listTutorials(ctx context.Context, params url.Values, opts database.ListOptions) ([]models.Tutorial, error) {
+	params.Set("select", tutorialSelect)
+	params.Set("order", "created_at.desc")
+	params.Set("instructions.order", "step_number.asc")
+	if opts.Status != "" {
+		params.Set("status", "eq."+opts.Status)
+	}
+	if opts.CreatorHandle != "" {
+		// Filtering on an embedded resource needs an inner join, or
+		// non-matching tutorials come back with a null creator
+		params.Set("select", strings.Replace(tutorialSelect, "creators(*)", "creators!inner(*)", 1))
+		params.Set("creator.handle", "eq."+opts.CreatorHandle)
+	}
+	if opts.Platform != "" {
+		params.Set("platform", "eq."+opts.Platform)
+	}
+	if opts.SoundType != "" {
+		params.Set("sound_type", "eq."+opts.SoundType)
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", fmt.Sprint(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		params.Set("offset", fmt.Sprint(opts.Offset))
+	}
+
+	var tutorials []models.Tutorial
+	if err := s.request(ctx, "GET", "/tutorials?"+params.Encode(), nil, &tutorials); err != nil {
+		return nil, err
+	}
+
+	return tutorials, nil
+}
+
+// quoteFilterValue wraps a PostgREST filter value in double quotes so that
+// reserved characters like commas and parentheses are matched literally
+func quoteFilterValue(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return `"` + escaped + `"`
+}
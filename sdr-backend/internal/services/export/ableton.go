@@ -0,0 +1,262 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// AbletonReport describes what went into an exported rack and what didn't
+type AbletonReport struct {
+	Rack      string            `json:"rack"`    // "instrument" or "audio_effect"
+	Devices   []string          `json:"devices"` // in chain order
+	Set       []ParameterReport `json:"set"`
+	Defaulted []ParameterReport `json:"defaulted"` // left at the device default, with the reason
+	Skipped   []StepReport      `json:"skipped"`   // steps whose device couldn't go in the rack
+}
+
+// ParameterReport is one instruction parameter and what the exporter did
+// with it
+type ParameterReport struct {
+	Step      int    `json:"step"`
+	Device    string `json:"device"`
+	Parameter string `json:"parameter"`
+	Raw       string `json:"raw"`
+	Value     string `json:"value,omitempty"` // as written to the preset
+	Reason    string `json:"reason,omitempty"`
+}
+
+// StepReport is an instruction the exporter couldn't use
+type StepReport struct {
+	Step   int    `json:"step"`
+	Device string `json:"device,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// rackDevice is a device in the chain being built
+type rackDevice struct {
+	name string
+	spec abletonDevice
+	node *node
+}
+
+// Ableton builds a gzipped Live device rack (.adg) chaining the recipe's
+// stock devices in step order, with the parameters it can map set. A device
+// used by several steps appears once with every step's settings applied.
+// Racks get an instrument chain when the recipe uses an instrument, which
+// goes first, and an audio effect chain otherwise.
+func Ableton(tutorial *models.Tutorial) (*File, *AbletonReport, error) {
+	report := &AbletonReport{
+		Set:       []ParameterReport{},
+		Defaulted: []ParameterReport{},
+		Skipped:   []StepReport{},
+	}
+
+	var (
+		effects    []*rackDevice
+		instrument *rackDevice
+		byName     = map[string]*rackDevice{}
+	)
+	for _, inst := range tutorial.Instructions {
+		if inst.AbletonDevice == "" {
+			continue
+		}
+
+		d, ok := byName[inst.AbletonDevice]
+		if !ok {
+			spec, supported := abletonDevices[inst.AbletonDevice]
+			switch {
+			case !supported:
+				report.Skipped = append(report.Skipped, StepReport{
+					Step:   inst.StepNumber,
+					Device: inst.AbletonDevice,
+					Reason: "not a Live device the exporter supports",
+				})
+				continue
+			case spec.instrument && instrument != nil:
+				report.Skipped = append(report.Skipped, StepReport{
+					Step:   inst.StepNumber,
+					Device: inst.AbletonDevice,
+					Reason: "the rack already has an instrument (" + instrument.name + ")",
+				})
+				continue
+			}
+
+			d = &rackDevice{name: inst.AbletonDevice, spec: spec, node: el(spec.element, el("On", el("Manual").set("true")))}
+			byName[d.name] = d
+			if spec.instrument {
+				instrument = d
+			} else {
+				effects = append(effects, d)
+			}
+		}
+
+		for _, key := range sortedKeys(inst.Parameters) {
+			param := ParameterReport{
+				Step:      inst.StepNumber,
+				Device:    d.name,
+				Parameter: key,
+				Raw:       inst.Parameters[key].Raw,
+			}
+
+			m, ok := d.spec.params[key]
+			if !ok {
+				param.Reason = "no matching parameter in the preset format"
+				report.Defaulted = append(report.Defaulted, param)
+				continue
+			}
+
+			value, reason := m.value(inst.Parameters[key])
+			if reason != "" {
+				param.Reason = reason
+				report.Defaulted = append(report.Defaulted, param)
+				continue
+			}
+
+			d.node.child(m.path).child("Manual").set(value)
+			param.Value = value
+			report.Set = append(report.Set, param)
+		}
+	}
+
+	chain := effects
+	if instrument != nil {
+		chain = append([]*rackDevice{instrument}, effects...)
+	}
+	if len(chain) == 0 {
+		return nil, nil, ErrNothingToExport
+	}
+
+	rack, devices := abletonRack(tutorial.Title, instrument != nil)
+	for i, d := range chain {
+		d.node.attrs = []xml.Attr{{Name: xml.Name{Local: "Id"}, Value: strconv.Itoa(i)}}
+		devices.children = append(devices.children, d.node)
+		report.Devices = append(report.Devices, d.name)
+	}
+	report.Rack = "audio_effect"
+	if instrument != nil {
+		report.Rack = "instrument"
+	}
+
+	doc := el("Ableton", el("GroupDevicePreset", el("Device", rack)))
+	doc.attrs = []xml.Attr{
+		{Name: xml.Name{Local: "MajorVersion"}, Value: "5"},
+		{Name: xml.Name{Local: "MinorVersion"}, Value: "11.0_433"},
+		{Name: xml.Name{Local: "Creator"}, Value: "Ableton Live 11.0"},
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(xml.Header)); err != nil {
+		return nil, nil, err
+	}
+	enc := xml.NewEncoder(zw)
+	enc.Indent("", "\t")
+	if err := enc.Encode(doc); err != nil {
+		return nil, nil, fmt.Errorf("encode rack: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return &File{
+		Name:        filename(tutorial.Title, ".adg"),
+		ContentType: "application/octet-stream",
+		Data:        buf.Bytes(),
+	}, report, nil
+}
+
+// abletonRack returns an empty rack with a single chain, and the chain's
+// device list. Live fills in every element left out with its default, which
+// is what leaves unmapped parameters at their defaults.
+func abletonRack(title string, instrument bool) (rack, devices *node) {
+	group, branch, chain := "AudioEffectGroupDevice", "AudioEffectBranch", "AudioToAudioDeviceChain"
+	if instrument {
+		group, branch, chain = "InstrumentGroupDevice", "InstrumentBranch", "MidiToAudioDeviceChain"
+	}
+
+	devices = el("Devices")
+	rack = el(group,
+		el("On", el("Manual").set("true")),
+		el("UserName").set(title),
+		el("Branches", el(branch,
+			el("Name").set(title),
+			el("DeviceChain", el(chain, devices)),
+		)),
+	)
+	rack.attrs = []xml.Attr{{Name: xml.Name{Local: "Id"}, Value: "0"}}
+	return rack, devices
+}
+
+func sortedKeys(params map[string]models.ParameterValue) []string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// node is an element in Live's preset XML, where values are stored in a
+// Value attribute rather than as text
+type node struct {
+	name     string
+	attrs    []xml.Attr
+	children []*node
+}
+
+func el(name string, children ...*node) *node {
+	return &node{name: name, children: children}
+}
+
+// set stores value in the node's Value attribute
+func (n *node) set(value string) *node {
+	for i, attr := range n.attrs {
+		if attr.Name.Local == "Value" {
+			n.attrs[i].Value = value
+			return n
+		}
+	}
+	n.attrs = append(n.attrs, xml.Attr{Name: xml.Name{Local: "Value"}, Value: value})
+	return n
+}
+
+// child returns the descendant at a "/"-separated path, creating it if
+// needed
+func (n *node) child(path string) *node {
+	current := n
+	for _, name := range strings.Split(path, "/") {
+		var next *node
+		for _, c := range current.children {
+			if c.name == name {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			next = el(name)
+			current.children = append(current.children, next)
+		}
+		current = next
+	}
+	return current
+}
+
+func (n *node) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: n.name}, Attr: n.attrs}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, c := range n.children {
+		if err := e.Encode(c); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
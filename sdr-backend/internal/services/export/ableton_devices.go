@@ -0,0 +1,206 @@
+package export
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// abletonDevice is how a stock Live device is written to a preset
+type abletonDevice struct {
+	element    string // XML element name, which is often an older internal name
+	instrument bool
+	params     map[string]abletonParam // keyed by the catalog's parameter name
+}
+
+// abletonParam is where a catalog parameter is stored in the device's XML
+// and how a normalized value is converted to what Live stores
+type abletonParam struct {
+	path    string
+	unit    string                // unit the normalized value must be in
+	convert func(float64) float64 // nil stores the value as is
+	options []string              // menus, in the order Live numbers them
+	toggle  bool
+}
+
+// abletonDevices covers the stock devices the exporter can write. Devices
+// without params are added to the rack at their defaults.
+var abletonDevices = map[string]abletonDevice{
+	"Wavetable": {element: "InstrumentVector", instrument: true, params: map[string]abletonParam{
+		"Osc 1 Position":     number("Voice_Oscillator1_Wavetables_WavePosition", models.UnitPercent, fraction),
+		"Osc 1 Transpose":    number("Voice_Oscillator1_Pitch_Transpose", models.UnitSemitones, nil),
+		"Osc 1 Gain":         number("Voice_Oscillator1_Gain", models.UnitPercent, fraction),
+		"Osc 2 Position":     number("Voice_Oscillator2_Wavetables_WavePosition", models.UnitPercent, fraction),
+		"Osc 2 Transpose":    number("Voice_Oscillator2_Pitch_Transpose", models.UnitSemitones, nil),
+		"Osc 2 Gain":         number("Voice_Oscillator2_Gain", models.UnitPercent, fraction),
+		"Sub Gain":           number("Voice_SubOscillator_Gain", models.UnitPercent, fraction),
+		"Filter 1 Frequency": number("Voice_Filter1_Frequency", models.UnitHertz, nil),
+		"Filter 1 Resonance": number("Voice_Filter1_Resonance", models.UnitPercent, fraction),
+		"Filter 1 Drive":     number("Voice_Filter1_Drive", models.UnitDecibels, nil),
+		"Filter 2 Frequency": number("Voice_Filter2_Frequency", models.UnitHertz, nil),
+		"Filter 2 Resonance": number("Voice_Filter2_Resonance", models.UnitPercent, fraction),
+		"Amp Attack":         number("Voice_Modulators_AmpEnvelope_Times_Attack", models.UnitMillis, seconds),
+		"Amp Decay":          number("Voice_Modulators_AmpEnvelope_Times_Decay", models.UnitMillis, seconds),
+		"Amp Sustain":        number("Voice_Modulators_AmpEnvelope_Sustain", models.UnitPercent, fraction),
+		"Amp Release":        number("Voice_Modulators_AmpEnvelope_Times_Release", models.UnitMillis, seconds),
+		"Unison Amount":      number("Voice_Unison_Amount", models.UnitPercent, fraction),
+		"Glide":              number("Voice_Global_Glide", models.UnitMillis, seconds),
+		"Volume":             number("Volume", models.UnitDecibels, nil),
+	}},
+	"Operator": {element: "Operator", instrument: true, params: map[string]abletonParam{
+		"Osc A Coarse": number("Operator.0/Tune/Coarse", "", nil),
+		"Osc A Fine":   number("Operator.0/Tune/Fine", "", nil),
+		"Osc B Coarse": number("Operator.1/Tune/Coarse", "", nil),
+		"Osc B Fine":   number("Operator.1/Tune/Fine", "", nil),
+		"Osc C Coarse": number("Operator.2/Tune/Coarse", "", nil),
+		"Osc C Fine":   number("Operator.2/Tune/Fine", "", nil),
+		"Osc D Coarse": number("Operator.3/Tune/Coarse", "", nil),
+		"Osc D Fine":   number("Operator.3/Tune/Fine", "", nil),
+		"Algorithm":    number("Globals/Algorithm", "", func(v float64) float64 { return v - 1 }),
+		"Transpose":    number("Globals/Transpose", models.UnitSemitones, nil),
+	}},
+	"Analog":       {element: "UltraAnalog", instrument: true},
+	"Drift":        {element: "Drift", instrument: true},
+	"Simpler":      {element: "OriginalSimpler", instrument: true},
+	"Sampler":      {element: "MultiSampler", instrument: true},
+	"Collision":    {element: "Collision", instrument: true},
+	"Tension":      {element: "StringStudio", instrument: true},
+	"Electric":     {element: "LoungeLizard", instrument: true},
+	"Impulse":      {element: "InstrumentImpulse", instrument: true},
+	"Drum Sampler": {element: "DrumCell", instrument: true},
+
+	"Saturator": {element: "Saturator", params: map[string]abletonParam{
+		"Drive":     number("PreDrive", models.UnitDecibels, nil),
+		"Type":      menu("Type", "Analog Clip", "Soft Sine", "Medium Curve", "Hard Curve", "Sinoid Fold", "Digital Clip", "Waveshaper"),
+		"Frequency": number("ColorFrequency", models.UnitHertz, nil),
+		"Width":     number("ColorWidth", models.UnitPercent, fraction),
+		"Depth":     number("ColorDepth", models.UnitDecibels, nil),
+		"Output":    number("PostDrive", models.UnitDecibels, nil),
+		"Dry/Wet":   number("DryWet", models.UnitPercent, fraction),
+		"Soft Clip": toggle("PostClip"),
+	}},
+	"Auto Filter": {element: "AutoFilter", params: map[string]abletonParam{
+		"Frequency":       number("Cutoff", models.UnitHertz, pitch),
+		"Resonance":       number("Resonance", models.UnitPercent, fraction),
+		"Filter Type":     menu("FilterType", "Lowpass", "Highpass", "Bandpass", "Notch", "Morph"),
+		"Envelope Amount": number("EnvelopeAmount", models.UnitPercent, fraction),
+		"LFO Amount":      number("LfoAmount", models.UnitPercent, fraction),
+		"LFO Rate":        number("LfoFrequency", models.UnitHertz, nil),
+	}},
+	"Compressor": {element: "Compressor2", params: map[string]abletonParam{
+		"Threshold":   number("Threshold", models.UnitDecibels, gain),
+		"Ratio":       number("Ratio", models.UnitRatio, nil),
+		"Attack":      number("Attack", models.UnitMillis, nil),
+		"Release":     number("Release", models.UnitMillis, nil),
+		"Knee":        number("Knee", models.UnitDecibels, nil),
+		"Output Gain": number("Gain", models.UnitDecibels, nil),
+		"Dry/Wet":     number("DryWet", models.UnitPercent, fraction),
+	}},
+	"Utility": {element: "StereoGain", params: map[string]abletonParam{
+		"Gain":                number("Gain", models.UnitDecibels, nil),
+		"Width":               number("StereoWidth", models.UnitPercent, fraction),
+		"Mono":                toggle("Mono"),
+		"Bass Mono Frequency": number("BassMonoFrequency", models.UnitHertz, nil),
+		"Balance":             number("Balance", models.UnitPercent, fraction),
+	}},
+	"Reverb": {element: "Reverb", params: map[string]abletonParam{
+		"Decay Time": number("DecayTime", models.UnitMillis, nil),
+		"Predelay":   number("PreDelay", models.UnitMillis, nil),
+		"Size":       number("RoomSize", models.UnitPercent, nil),
+		"Dry/Wet":    number("MixDirect", models.UnitPercent, fraction),
+		"Freeze":     toggle("FreezeOn"),
+	}},
+	"EQ Eight":           {element: "Eq8"},
+	"EQ Three":           {element: "FilterEQ3"},
+	"Channel EQ":         {element: "ChannelEq"},
+	"Glue Compressor":    {element: "GlueCompressor"},
+	"Multiband Dynamics": {element: "MultibandDynamics"},
+	"Limiter":            {element: "Limiter"},
+	"Gate":               {element: "Gate"},
+	"Drum Buss":          {element: "DrumBuss"},
+	"Overdrive":          {element: "Overdrive"},
+	"Pedal":              {element: "Pedal"},
+	"Roar":               {element: "Roar"},
+	"Redux":              {element: "Redux2"},
+	"Erosion":            {element: "Erosion"},
+	"Dynamic Tube":       {element: "Tube"},
+	"Vinyl Distortion":   {element: "Vinyl"},
+	"Amp":                {element: "Amp"},
+	"Cabinet":            {element: "Cabinet"},
+	"Hybrid Reverb":      {element: "Hybrid"},
+	"Delay":              {element: "Delay"},
+	"Echo":               {element: "Echo"},
+	"Filter Delay":       {element: "FilterDelay"},
+	"Grain Delay":        {element: "GrainDelay"},
+	"Beat Repeat":        {element: "BeatRepeat"},
+	"Chorus-Ensemble":    {element: "Chorus2"},
+	"Phaser-Flanger":     {element: "PhaserNew"},
+	"Auto Pan":           {element: "AutoPan"},
+	"Frequency Shifter":  {element: "FrequencyShifter"},
+	"Corpus":             {element: "Corpus"},
+	"Resonators":         {element: "Resonator"},
+	"Vocoder":            {element: "Vocoder"},
+	"Shifter":            {element: "Shifter"},
+}
+
+func number(path, unit string, convert func(float64) float64) abletonParam {
+	return abletonParam{path: path, unit: unit, convert: convert}
+}
+
+func menu(path string, options ...string) abletonParam {
+	return abletonParam{path: path, options: options}
+}
+
+func toggle(path string) abletonParam {
+	return abletonParam{path: path, toggle: true}
+}
+
+// Conversions from normalized units to Live's stored values
+func fraction(percent float64) float64 { return percent / 100 }
+func seconds(ms float64) float64       { return ms / 1000 }
+func gain(db float64) float64          { return math.Pow(10, db/20) }
+func pitch(hz float64) float64         { return 69 + 12*math.Log2(hz/440) } // as a MIDI note
+
+// value returns what to store for v, or why it can't be stored
+func (p abletonParam) value(v models.ParameterValue) (string, string) {
+	if v.NeedsReview {
+		return "", "flagged for review"
+	}
+
+	switch {
+	case p.toggle:
+		switch optionKey(v.Raw) {
+		case "on", "true", "yes", "enabled":
+			return "true", ""
+		case "off", "false", "no", "disabled":
+			return "false", ""
+		}
+		return "", fmt.Sprintf("couldn't tell on or off from %q", v.Raw)
+
+	case p.options != nil:
+		for i, option := range p.options {
+			if optionKey(option) == optionKey(v.Raw) {
+				return strconv.Itoa(i), ""
+			}
+		}
+		return "", fmt.Sprintf("%q isn't one of the device's options", v.Raw)
+	}
+
+	if v.Value == nil {
+		return "", "no numeric value"
+	}
+	if v.Unit != p.unit {
+		return "", fmt.Sprintf("value is in %q, expected %q", v.Unit, p.unit)
+	}
+
+	value := *v.Value
+	if p.unit == models.UnitHertz && value <= 0 {
+		return "", "frequency must be above 0 Hz"
+	}
+	if p.convert != nil {
+		value = p.convert(value)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64), ""
+}
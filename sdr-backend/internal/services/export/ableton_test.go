@@ -0,0 +1,261 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// xmlNode is a generic element for reading exported presets back
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []xmlNode  `xml:",any"`
+}
+
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// find returns the descendant at a "/"-separated path, or nil
+func (n *xmlNode) find(path string) *xmlNode {
+	current := n
+	for _, name := range strings.Split(path, "/") {
+		var next *xmlNode
+		for i := range current.Children {
+			if current.Children[i].XMLName.Local == name {
+				next = &current.Children[i]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// value is a normalized parameter value as the units package produces
+func value(v float64, unit, raw string) models.ParameterValue {
+	return models.ParameterValue{Value: &v, Unit: unit, Raw: raw, Confidence: 1}
+}
+
+// said is a parameter with no numeric value, like a menu option
+func said(raw string) models.ParameterValue {
+	return models.ParameterValue{Raw: raw}
+}
+
+func readRack(t *testing.T, file *File) *xmlNode {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(file.Data))
+	if err != nil {
+		t.Fatalf("not gzipped: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte(xml.Header)) {
+		t.Errorf("missing XML header")
+	}
+
+	var doc xmlNode
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("malformed XML: %v", err)
+	}
+	if doc.XMLName.Local != "Ableton" {
+		t.Fatalf("root element = %s", doc.XMLName.Local)
+	}
+	return &doc
+}
+
+func TestAbleton(t *testing.T) {
+	tests := []struct {
+		name          string
+		instructions  []models.Instruction
+		wantGroup     string   // rack element under GroupDevicePreset/Device
+		wantChain     string   // path from the rack to its device list
+		wantDevices   []string // XML elements in chain order
+		wantValues    map[string]string
+		wantDefaulted []string // "step parameter"
+		wantSkipped   []int
+	}{
+		{
+			name: "instrument first then effects in step order",
+			instructions: []models.Instruction{
+				{StepNumber: 1, AbletonDevice: "Saturator", Parameters: map[string]models.ParameterValue{
+					"Drive": value(6, models.UnitDecibels, "6 dB"),
+				}},
+				{StepNumber: 2, AbletonDevice: "Wavetable", Parameters: map[string]models.ParameterValue{
+					"Filter 1 Frequency": value(800, models.UnitHertz, "800 Hz"),
+					"Amp Attack":         value(250, models.UnitMillis, "250ms"),
+				}},
+				{StepNumber: 3, AbletonDevice: "Auto Filter", Parameters: map[string]models.ParameterValue{
+					"Frequency": value(0, models.UnitHertz, "0 Hz"),
+					"Resonance": value(40, models.UnitPercent, "40%"),
+				}},
+				{StepNumber: 4, AbletonDevice: "Saturator", Parameters: map[string]models.ParameterValue{
+					"Type":      said("soft sine"),
+					"Soft Clip": said("on"),
+				}},
+				{StepNumber: 5, AbletonDevice: "Serum"},
+				{StepNumber: 6, AbletonDevice: "Operator"},
+				{StepNumber: 7, Description: "Play a low note"},
+			},
+			wantGroup:   "InstrumentGroupDevice",
+			wantChain:   "Branches/InstrumentBranch/DeviceChain/MidiToAudioDeviceChain/Devices",
+			wantDevices: []string{"InstrumentVector", "Saturator", "AutoFilter"},
+			wantValues: map[string]string{
+				"InstrumentVector/Voice_Filter1_Frequency/Manual":                   "800",
+				"InstrumentVector/Voice_Modulators_AmpEnvelope_Times_Attack/Manual": "0.25",
+				"Saturator/PreDrive/Manual":                                         "6",
+				"Saturator/Type/Manual":                                             "1",
+				"Saturator/PostClip/Manual":                                         "true",
+				"AutoFilter/Resonance/Manual":                                       "0.4",
+			},
+			wantDefaulted: []string{"3 Frequency"},
+			wantSkipped:   []int{5, 6},
+		},
+		{
+			name: "effects only",
+			instructions: []models.Instruction{
+				{StepNumber: 1, AbletonDevice: "Reverb", Parameters: map[string]models.ParameterValue{
+					"Dry/Wet":  value(25, models.UnitPercent, "25%"),
+					"Predelay": value(20, models.UnitPercent, "20%"), // wrong unit
+					"Shimmer":  value(50, models.UnitPercent, "50%"), // not in the format
+				}},
+				{StepNumber: 2, AbletonDevice: "Utility", Parameters: map[string]models.ParameterValue{
+					"Mono": said("on"),
+					"Gain": {Value: new(float64), Unit: models.UnitDecibels, Raw: "loud", NeedsReview: true},
+				}},
+				{StepNumber: 3, AbletonDevice: "Compressor", Parameters: map[string]models.ParameterValue{
+					"Threshold": value(-20, models.UnitDecibels, "-20 dB"),
+				}},
+			},
+			wantGroup:   "AudioEffectGroupDevice",
+			wantChain:   "Branches/AudioEffectBranch/DeviceChain/AudioToAudioDeviceChain/Devices",
+			wantDevices: []string{"Reverb", "StereoGain", "Compressor2"},
+			wantValues: map[string]string{
+				"Reverb/MixDirect/Manual":      "0.25",
+				"StereoGain/Mono/Manual":       "true",
+				"Compressor2/Threshold/Manual": "0.1",
+			},
+			wantDefaulted: []string{"1 Predelay", "1 Shimmer", "2 Gain"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, report, err := Ableton(&models.Tutorial{Title: "Reese Bass", Instructions: tt.instructions})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if file.Name != "Reese Bass.adg" {
+				t.Errorf("Name = %q", file.Name)
+			}
+
+			doc := readRack(t, file)
+			rack := doc.find("GroupDevicePreset/Device/" + tt.wantGroup)
+			if rack == nil {
+				t.Fatalf("no %s in the preset", tt.wantGroup)
+			}
+			devices := rack.find(tt.wantChain)
+			if devices == nil {
+				t.Fatalf("no device chain at %s", tt.wantChain)
+			}
+
+			var got []string
+			for i, d := range devices.Children {
+				got = append(got, d.XMLName.Local)
+				if id := d.attr("Id"); id != strconv.Itoa(i) {
+					t.Errorf("device %d has Id %q", i, id)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantDevices, ",") {
+				t.Errorf("chain = %v, want %v", got, tt.wantDevices)
+			}
+			if len(report.Devices) != len(tt.wantDevices) {
+				t.Errorf("report.Devices = %v", report.Devices)
+			}
+
+			for path, want := range tt.wantValues {
+				n := devices.find(path)
+				if n == nil {
+					t.Errorf("%s missing", path)
+					continue
+				}
+				if got := n.attr("Value"); got != want {
+					t.Errorf("%s = %q, want %q", path, got, want)
+				}
+			}
+			if len(report.Set) != len(tt.wantValues) {
+				t.Errorf("report.Set = %+v, want %d parameters", report.Set, len(tt.wantValues))
+			}
+
+			var defaulted []string
+			for _, p := range report.Defaulted {
+				defaulted = append(defaulted, strconv.Itoa(p.Step)+" "+p.Parameter)
+				if p.Reason == "" {
+					t.Errorf("defaulted %+v has no reason", p)
+				}
+			}
+			if strings.Join(defaulted, ",") != strings.Join(tt.wantDefaulted, ",") {
+				t.Errorf("defaulted = %v, want %v", defaulted, tt.wantDefaulted)
+			}
+
+			var skipped []int
+			for _, s := range report.Skipped {
+				skipped = append(skipped, s.Step)
+			}
+			if fmt.Sprint(skipped) != fmt.Sprint(tt.wantSkipped) {
+				t.Errorf("skipped = %+v, want steps %v", report.Skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestAbletonRejectsNonPositiveFrequencies(t *testing.T) {
+	for _, hz := range []float64{0, -100} {
+		file, report, err := Ableton(&models.Tutorial{Title: "Sweep", Instructions: []models.Instruction{
+			{StepNumber: 1, AbletonDevice: "Auto Filter", Parameters: map[string]models.ParameterValue{
+				"Frequency": value(hz, models.UnitHertz, strconv.FormatFloat(hz, 'f', -1, 64)+" Hz"),
+			}},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Set) != 0 || len(report.Defaulted) != 1 || !strings.Contains(report.Defaulted[0].Reason, "above 0 Hz") {
+			t.Errorf("%g Hz: report = %+v", hz, report)
+		}
+		autoFilter := readRack(t, file).find("GroupDevicePreset/Device/AudioEffectGroupDevice/Branches/" +
+			"AudioEffectBranch/DeviceChain/AudioToAudioDeviceChain/Devices/AutoFilter")
+		if autoFilter == nil || autoFilter.find("Cutoff") != nil {
+			t.Errorf("%g Hz: want an Auto Filter with the default cutoff", hz)
+		}
+	}
+}
+
+func TestAbletonNothingToExport(t *testing.T) {
+	_, _, err := Ableton(&models.Tutorial{Title: "Serum Bass", Instructions: []models.Instruction{
+		{StepNumber: 1, AbletonDevice: "Serum"},
+		{StepNumber: 2, Description: "Play a low note"},
+	}})
+	if !errors.Is(err, ErrNothingToExport) {
+		t.Errorf("err = %v, want ErrNothingToExport", err)
+	}
+}
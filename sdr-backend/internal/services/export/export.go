@@ -0,0 +1,40 @@
+package export
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrNothingToExport is returned when a recipe has nothing the format can
+// represent
+var ErrNothingToExport = errors.New("nothing to export")
+
+// File is an exported recipe ready to download
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// filename turns a recipe title into a safe file name with ext
+func filename(title, ext string) string {
+	name := strings.Join(strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_'
+	}), " ")
+	if name == "" {
+		name = "recipe"
+	}
+	return name + ext
+}
+
+// optionKey lowercases name and drops everything but letters and digits,
+// so "Analog Clip" and "analog-clip" compare equal
+func optionKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
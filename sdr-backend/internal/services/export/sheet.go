@@ -0,0 +1,241 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"math"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// Cheat sheet formats
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// markdownFuncs escape values for where the Markdown template puts them
+var markdownFuncs = texttemplate.FuncMap{
+	"cell":  markdownCell,
+	"line":  markdownLine,
+	"quote": markdownQuote,
+}
+
+var (
+	markdownSheet = texttemplate.Must(texttemplate.New("recipe.md.tmpl").
+			Funcs(markdownFuncs).
+			ParseFS(templates, "templates/recipe.md.tmpl"))
+	htmlSheet = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/recipe.html.tmpl"))
+)
+
+// sheet is a recipe flattened into what the cheat sheet templates show
+type sheet struct {
+	Title     string
+	Creator   string
+	SoundType string
+	Source    string
+	Devices   []sheetDevice
+	Steps     []sheetStep
+}
+
+type sheetDevice struct {
+	Name  string
+	Steps string // "1, 3, 4"
+}
+
+type sheetStep struct {
+	Number      int
+	Description string
+	Device      string
+	Time        string // "0:42", when the step's timestamp is known
+	Parameters  []sheetParameter
+	Notes       string
+}
+
+type sheetParameter struct {
+	Name   string
+	Value  string
+	Raw    string
+	Review bool
+}
+
+// Sheet renders a recipe as a Markdown or printable HTML cheat sheet
+func Sheet(tutorial *models.Tutorial, format string) (*File, error) {
+	var buf bytes.Buffer
+	data := newSheet(tutorial)
+
+	switch format {
+	case FormatMarkdown:
+		if err := markdownSheet.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("render markdown: %w", err)
+		}
+		return &File{
+			Name:        filename(tutorial.Title, ".md"),
+			ContentType: "text/markdown; charset=utf-8",
+			Data:        buf.Bytes(),
+		}, nil
+
+	case FormatHTML:
+		if err := htmlSheet.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("render html: %w", err)
+		}
+		return &File{
+			Name:        filename(tutorial.Title, ".html"),
+			ContentType: "text/html; charset=utf-8",
+			Data:        buf.Bytes(),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// SheetZip renders every recipe in format and bundles them into a zip named
+// name. Recipes with the same title get numbered file names.
+func SheetZip(name string, tutorials []models.Tutorial, format string) (*File, error) {
+	if len(tutorials) == 0 {
+		return nil, ErrNothingToExport
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	used := map[string]int{}
+	for i := range tutorials {
+		file, err := Sheet(&tutorials[i], format)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := file.Name
+		if n := used[strings.ToLower(entry)]; n > 0 {
+			ext := entry[strings.LastIndexByte(entry, '.'):]
+			entry = fmt.Sprintf("%s %d%s", strings.TrimSuffix(entry, ext), n+1, ext)
+		}
+		used[strings.ToLower(file.Name)]++
+
+		w, err := zw.Create(entry)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(file.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Name:        filename(name, ".zip"),
+		ContentType: "application/zip",
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+func newSheet(tutorial *models.Tutorial) sheet {
+	s := sheet{
+		Title:     tutorial.Title,
+		SoundType: tutorial.SoundType,
+		Source:    tutorial.SourceURL,
+	}
+	if c := tutorial.Creator; c != nil {
+		s.Creator = "@" + c.Handle
+		if c.DisplayName != "" && c.DisplayName != c.Handle {
+			s.Creator = c.DisplayName + " (@" + c.Handle + ")"
+		}
+	}
+
+	deviceSteps := map[string][]string{}
+	for _, inst := range tutorial.Instructions {
+		step := sheetStep{
+			Number:      inst.StepNumber,
+			Description: inst.Description,
+			Device:      inst.AbletonDevice,
+			Notes:       inst.Notes,
+		}
+		if inst.StartTime != nil {
+			step.Time = timestamp(*inst.StartTime)
+		}
+		for _, key := range sortedKeys(inst.Parameters) {
+			v := inst.Parameters[key]
+			step.Parameters = append(step.Parameters, sheetParameter{
+				Name:   key,
+				Value:  displayValue(v),
+				Raw:    v.Raw,
+				Review: v.NeedsReview,
+			})
+		}
+		s.Steps = append(s.Steps, step)
+
+		if inst.AbletonDevice == "" {
+			continue
+		}
+		if _, seen := deviceSteps[inst.AbletonDevice]; !seen {
+			s.Devices = append(s.Devices, sheetDevice{Name: inst.AbletonDevice})
+		}
+		deviceSteps[inst.AbletonDevice] = append(deviceSteps[inst.AbletonDevice], strconv.Itoa(inst.StepNumber))
+	}
+	for i := range s.Devices {
+		s.Devices[i].Steps = strings.Join(deviceSteps[s.Devices[i].Name], ", ")
+	}
+
+	return s
+}
+
+// displayValue formats a normalized value with its unit, falling back to
+// what the creator said when it couldn't be read
+func displayValue(v models.ParameterValue) string {
+	if v.Value == nil {
+		return v.Raw
+	}
+
+	value := strconv.FormatFloat(math.Round(*v.Value*1000)/1000, 'f', -1, 64)
+	switch v.Unit {
+	case "":
+		return value
+	case models.UnitPercent:
+		return value + "%"
+	case models.UnitRatio:
+		return value + ":1"
+	case models.UnitNote:
+		if *v.Value > 0 {
+			return "1/" + strconv.FormatFloat(math.Round(1 / *v.Value * 100)/100, 'f', -1, 64)
+		}
+		return value
+	}
+	return value + " " + v.Unit
+}
+
+// timestamp formats seconds into the video as m:ss
+func timestamp(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
+
+// markdownLine flattens a value onto one line, so it can't end the heading
+// or list item it's in
+func markdownLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// markdownCell keeps a value from breaking out of its table cell
+func markdownCell(value string) string {
+	return markdownLine(strings.ReplaceAll(value, "|", `\|`))
+}
+
+// markdownQuote quotes every line of a value, not just the first
+func markdownQuote(value string) string {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(value, "\r\n", "\n")), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+strings.TrimSpace(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
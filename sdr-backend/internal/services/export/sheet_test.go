@@ -0,0 +1,138 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+func sheetTutorial(title string) *models.Tutorial {
+	start := 42.0
+	return &models.Tutorial{
+		Title:     title,
+		SoundType: "bass",
+		SourceURL: "https://www.tiktok.com/@producer/video/1",
+		Creator:   &models.Creator{Handle: "producer", DisplayName: "Producer"},
+		Instructions: []models.Instruction{
+			{StepNumber: 1, Description: "Load Wavetable", AbletonDevice: "Wavetable", StartTime: &start,
+				Parameters: map[string]models.ParameterValue{
+					"Filter 1 Frequency": value(800, models.UnitHertz, "800 Hz"),
+					"Unison":             {Raw: "a | lot", NeedsReview: true},
+				}},
+			{StepNumber: 2, Description: "Add some grit\nwith a saturator", AbletonDevice: "Saturator",
+				Notes: "Keep it subtle.\nBack off the drive\r\nif it clips."},
+			{StepNumber: 3, Description: "Resample", AbletonDevice: "Wavetable"},
+		},
+	}
+}
+
+func TestSheetMarkdown(t *testing.T) {
+	file, err := Sheet(sheetTutorial("Reese <Bass>"), FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "Reese Bass.md" || !strings.HasPrefix(file.ContentType, "text/markdown") {
+		t.Errorf("file = %q, %q", file.Name, file.ContentType)
+	}
+
+	md := string(file.Data)
+	for _, want := range []string{
+		"# Reese <Bass>\n",
+		"- **Creator:** Producer (@producer)\n",
+		"| Wavetable | 1, 3 |\n",
+		"### 1. Load Wavetable\n",
+		"*Wavetable* · 0:42\n",
+		"| Filter 1 Frequency | 800 Hz | 800 Hz |\n",
+		"| Unison (needs review) | a \\| lot | a \\| lot |\n",
+		"### 2. Add some grit with a saturator\n",
+		"> Keep it subtle.\n> Back off the drive\n> if it clips.\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown is missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestSheetHTML(t *testing.T) {
+	file, err := Sheet(sheetTutorial("Reese <Bass>"), FormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "Reese Bass.html" || !strings.HasPrefix(file.ContentType, "text/html") {
+		t.Errorf("file = %q, %q", file.Name, file.ContentType)
+	}
+
+	html := string(file.Data)
+	for _, want := range []string{
+		"<h1>Reese &lt;Bass&gt;</h1>",
+		`<a href="https://www.tiktok.com/@producer/video/1">`,
+		"<h3>1. Load Wavetable</h3>",
+		`<tr class="review" title="Needs review"><td>Unison</td>`,
+		`<p class="notes">Keep it subtle.`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("html is missing %q:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "<Bass>") {
+		t.Errorf("title isn't escaped:\n%s", html)
+	}
+}
+
+func TestSheetUnknownFormat(t *testing.T) {
+	if _, err := Sheet(sheetTutorial("Reese Bass"), "pdf"); err == nil {
+		t.Error("want an error for an unknown format")
+	}
+}
+
+func TestSheetZip(t *testing.T) {
+	tutorials := []models.Tutorial{
+		*sheetTutorial("Reese Bass"),
+		*sheetTutorial("Pluck"),
+		*sheetTutorial("reese bass"),
+		*sheetTutorial("Reese Bass"),
+	}
+	file, err := SheetZip("My Recipes", tutorials, FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "My Recipes.zip" || file.ContentType != "application/zip" {
+		t.Errorf("file = %q, %q", file.Name, file.ContentType)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(file.Data), int64(len(file.Data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(data, []byte("# ")) {
+			t.Errorf("%s isn't a cheat sheet: %q", f.Name, data)
+		}
+	}
+	want := []string{"Reese Bass.md", "Pluck.md", "reese bass 2.md", "Reese Bass 3.md"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("entries = %q, want %q", names, want)
+	}
+}
+
+func TestSheetZipNothingToExport(t *testing.T) {
+	if _, err := SheetZip("Empty", nil, FormatMarkdown); !errors.Is(err, ErrNothingToExport) {
+		t.Errorf("err = %v, want ErrNothingToExport", err)
+	}
+}
@@ -0,0 +1,200 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// VitalReport describes which instruction parameters made it into an
+// exported Vital preset
+type VitalReport struct {
+	Set       []ParameterReport `json:"set"`
+	Defaulted []ParameterReport `json:"defaulted"` // left at Vital's default, with the reason
+	Unmapped  []StepReport      `json:"unmapped"`  // instructions that set nothing in the preset
+}
+
+// vitalPreset is the .vital file format. Settings left out load with
+// Vital's defaults.
+type vitalPreset struct {
+	Author       string             `json:"author"`
+	Comments     string             `json:"comments"`
+	PresetName   string             `json:"preset_name"`
+	PresetStyle  string             `json:"preset_style"`
+	SynthVersion string             `json:"synth_version"`
+	Settings     map[string]float64 `json:"settings"`
+}
+
+// vitalParam is the preset setting a catalog parameter is stored in
+type vitalParam struct {
+	setting string
+	unit    string                // unit the normalized value must be in
+	convert func(float64) float64 // nil stores the value as is
+	enable  string                // module switch to turn on when the setting is used
+}
+
+// vitalParams maps the catalog's Vital parameters onto preset settings
+var vitalParams = map[string]vitalParam{
+	"Osc 1 Frame":         {"osc_1_wave_frame", "", nil, "osc_1_on"},
+	"Osc 1 Transpose":     {"osc_1_transpose", models.UnitSemitones, nil, "osc_1_on"},
+	"Osc 1 Tune":          {"osc_1_tune", models.UnitCents, fraction, "osc_1_on"},
+	"Osc 1 Level":         {"osc_1_level", models.UnitPercent, fraction, "osc_1_on"},
+	"Osc 1 Unison Voices": {"osc_1_unison_voices", "", nil, "osc_1_on"},
+	"Osc 1 Unison Detune": {"osc_1_unison_detune", models.UnitPercent, detune, "osc_1_on"},
+	"Osc 2 Frame":         {"osc_2_wave_frame", "", nil, "osc_2_on"},
+	"Osc 2 Transpose":     {"osc_2_transpose", models.UnitSemitones, nil, "osc_2_on"},
+	"Osc 2 Tune":          {"osc_2_tune", models.UnitCents, fraction, "osc_2_on"},
+	"Osc 2 Level":         {"osc_2_level", models.UnitPercent, fraction, "osc_2_on"},
+	"Osc 2 Unison Voices": {"osc_2_unison_voices", "", nil, "osc_2_on"},
+	"Osc 2 Unison Detune": {"osc_2_unison_detune", models.UnitPercent, detune, "osc_2_on"},
+	"Osc 3 Frame":         {"osc_3_wave_frame", "", nil, "osc_3_on"},
+	"Osc 3 Transpose":     {"osc_3_transpose", models.UnitSemitones, nil, "osc_3_on"},
+	"Osc 3 Level":         {"osc_3_level", models.UnitPercent, fraction, "osc_3_on"},
+	"Filter 1 Cutoff":     {"filter_1_cutoff", models.UnitHertz, pitch, "filter_1_on"},
+	"Filter 1 Resonance":  {"filter_1_resonance", models.UnitPercent, fraction, "filter_1_on"},
+	"Filter 1 Drive":      {"filter_1_drive", models.UnitDecibels, nil, "filter_1_on"},
+	"Filter 1 Mix":        {"filter_1_mix", models.UnitPercent, fraction, "filter_1_on"},
+	"Filter 2 Cutoff":     {"filter_2_cutoff", models.UnitHertz, pitch, "filter_2_on"},
+	"Filter 2 Resonance":  {"filter_2_resonance", models.UnitPercent, fraction, "filter_2_on"},
+	"Env 1 Attack":        {"env_1_attack", models.UnitMillis, envelopeTime, ""},
+	"Env 1 Decay":         {"env_1_decay", models.UnitMillis, envelopeTime, ""},
+	"Env 1 Sustain":       {"env_1_sustain", models.UnitPercent, fraction, ""},
+	"Env 1 Release":       {"env_1_release", models.UnitMillis, envelopeTime, ""},
+	"Env 2 Attack":        {"env_2_attack", models.UnitMillis, envelopeTime, ""},
+	"Env 2 Decay":         {"env_2_decay", models.UnitMillis, envelopeTime, ""},
+	"Env 2 Sustain":       {"env_2_sustain", models.UnitPercent, fraction, ""},
+	"Env 2 Release":       {"env_2_release", models.UnitMillis, envelopeTime, ""},
+	"Distortion Drive":    {"distortion_drive", models.UnitDecibels, nil, "distortion_on"},
+	"Distortion Mix":      {"distortion_mix", models.UnitPercent, fraction, "distortion_on"},
+	"Compressor Mix":      {"compressor_mix", models.UnitPercent, fraction, "compressor_on"},
+	"Chorus Mix":          {"chorus_dry_wet", models.UnitPercent, fraction, "chorus_on"},
+	"Flanger Mix":         {"flanger_dry_wet", models.UnitPercent, fraction, "flanger_on"},
+	"Phaser Mix":          {"phaser_dry_wet", models.UnitPercent, fraction, "phaser_on"},
+	"Delay Mix":           {"delay_dry_wet", models.UnitPercent, fraction, "delay_on"},
+	"Reverb Mix":          {"reverb_dry_wet", models.UnitPercent, fraction, "reverb_on"},
+	"Reverb Size":         {"reverb_size", models.UnitPercent, fraction, "reverb_on"},
+}
+
+// vitalStyles maps sound types onto Vital's preset styles
+var vitalStyles = map[string]string{
+	"bass":       "Bass",
+	"lead":       "Lead",
+	"pad":        "Pad",
+	"pluck":      "Pluck",
+	"arp":        "Sequence",
+	"keys":       "Keys",
+	"chord":      "Keys",
+	"kick":       "Percussion",
+	"snare":      "Percussion",
+	"hihat":      "Percussion",
+	"percussion": "Percussion",
+	"fx":         "SFX",
+	"texture":    "Experiment",
+}
+
+// Conversions from normalized units to Vital's stored values
+func detune(percent float64) float64  { return percent / 10 }                    // stored 0 to 10
+func envelopeTime(ms float64) float64 { return math.Pow(max(ms, 0)/1000, 0.25) } // seconds on a quartic curve
+
+// Vital builds a .vital preset from the recipe's Vital instructions. Later
+// steps override earlier ones that set the same parameter, and using any
+// setting of an oscillator, filter or effect switches that module on.
+func Vital(tutorial *models.Tutorial) (*File, *VitalReport, error) {
+	report := &VitalReport{
+		Set:       []ParameterReport{},
+		Defaulted: []ParameterReport{},
+		Unmapped:  []StepReport{},
+	}
+	settings := map[string]float64{}
+
+	usesVital := false
+	for _, inst := range tutorial.Instructions {
+		if inst.AbletonDevice != "Vital" {
+			reason := "doesn't use a device"
+			if inst.AbletonDevice != "" {
+				reason = "uses " + inst.AbletonDevice + ", not Vital"
+			}
+			report.Unmapped = append(report.Unmapped, StepReport{Step: inst.StepNumber, Device: inst.AbletonDevice, Reason: reason})
+			continue
+		}
+		usesVital = true
+
+		set := 0
+		for _, key := range sortedKeys(inst.Parameters) {
+			value := inst.Parameters[key]
+			param := ParameterReport{Step: inst.StepNumber, Device: inst.AbletonDevice, Parameter: key, Raw: value.Raw}
+
+			m, ok := vitalParams[key]
+			switch {
+			case !ok:
+				param.Reason = "no matching setting in the preset format"
+			case value.NeedsReview:
+				param.Reason = "flagged for review"
+			case value.Value == nil:
+				param.Reason = "no numeric value"
+			case value.Unit != m.unit:
+				param.Reason = fmt.Sprintf("value is in %q, expected %q", value.Unit, m.unit)
+			case m.unit == models.UnitHertz && *value.Value <= 0:
+				param.Reason = "frequency must be above 0 Hz"
+			}
+			if param.Reason != "" {
+				report.Defaulted = append(report.Defaulted, param)
+				continue
+			}
+
+			stored := *value.Value
+			if m.convert != nil {
+				stored = m.convert(stored)
+			}
+			settings[m.setting] = stored
+			if m.enable != "" {
+				settings[m.enable] = 1
+			}
+
+			param.Value = strconv.FormatFloat(stored, 'f', -1, 64)
+			report.Set = append(report.Set, param)
+			set++
+		}
+
+		if set == 0 {
+			reason := "has no parameter values"
+			if len(inst.Parameters) > 0 {
+				reason = "none of its parameters map to the preset"
+			}
+			report.Unmapped = append(report.Unmapped, StepReport{Step: inst.StepNumber, Device: inst.AbletonDevice, Reason: reason})
+		}
+	}
+	if !usesVital {
+		return nil, nil, ErrNothingToExport
+	}
+
+	preset := vitalPreset{
+		PresetName:   tutorial.Title,
+		PresetStyle:  vitalStyles[tutorial.SoundType],
+		SynthVersion: "1.5.5",
+		Settings:     settings,
+	}
+	if tutorial.Creator != nil {
+		preset.Author = tutorial.Creator.DisplayName
+		if preset.Author == "" {
+			preset.Author = "@" + tutorial.Creator.Handle
+		}
+	}
+	if tutorial.SourceURL != "" {
+		preset.Comments = "From " + tutorial.SourceURL
+	}
+
+	data, err := json.MarshalIndent(preset, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode preset: %w", err)
+	}
+
+	return &File{
+		Name:        filename(tutorial.Title, ".vital"),
+		ContentType: "application/json",
+		Data:        data,
+	}, report, nil
+}
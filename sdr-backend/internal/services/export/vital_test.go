@@ -0,0 +1,124 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+func TestVital(t *testing.T) {
+	tutorial := &models.Tutorial{
+		Title:     "Reese Bass",
+		SoundType: "bass",
+		SourceURL: "https://www.tiktok.com/@producer/video/1",
+		Creator:   &models.Creator{Handle: "producer"},
+		Instructions: []models.Instruction{
+			{StepNumber: 1, AbletonDevice: "Vital", Parameters: map[string]models.ParameterValue{
+				"Osc 1 Level":         value(80, models.UnitPercent, "80%"),
+				"Osc 1 Unison Detune": value(30, models.UnitPercent, "30%"),
+				"Env 1 Attack":        value(1000, models.UnitMillis, "1 second"),
+			}},
+			{StepNumber: 2, AbletonDevice: "Vital", Parameters: map[string]models.ParameterValue{
+				"Filter 1 Cutoff":    value(440, models.UnitHertz, "440 Hz"),
+				"Filter 1 Resonance": value(50, models.UnitPercent, "half"),
+				"Filter 2 Cutoff":    value(0, models.UnitHertz, "0 Hz"),
+				"Reverb Mix":         value(25, models.UnitMillis, "25ms"), // wrong unit
+				"Wobble":             value(1, "", "1"),                    // not in the format
+			}},
+			{StepNumber: 3, AbletonDevice: "Vital", Parameters: map[string]models.ParameterValue{
+				"Osc 1 Level": value(60, models.UnitPercent, "60%"), // overrides step 1
+			}},
+			{StepNumber: 4, AbletonDevice: "Vital"},
+			{StepNumber: 5, AbletonDevice: "Vital", Parameters: map[string]models.ParameterValue{
+				"Distortion Drive": {Raw: "lots", NeedsReview: true},
+			}},
+			{StepNumber: 6, AbletonDevice: "OTT"},
+			{StepNumber: 7, Description: "Bounce it"},
+		},
+	}
+
+	file, report, err := Vital(tutorial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "Reese Bass.vital" {
+		t.Errorf("Name = %q", file.Name)
+	}
+
+	var preset vitalPreset
+	if err := json.Unmarshal(file.Data, &preset); err != nil {
+		t.Fatalf("invalid preset JSON: %v", err)
+	}
+	if preset.PresetName != "Reese Bass" || preset.PresetStyle != "Bass" || preset.Author != "@producer" {
+		t.Errorf("preset = %+v", preset)
+	}
+
+	wantSettings := map[string]float64{
+		"osc_1_level":         0.6,
+		"osc_1_unison_detune": 3,
+		"osc_1_on":            1,
+		"env_1_attack":        1,
+		"filter_1_cutoff":     69,
+		"filter_1_resonance":  0.5,
+		"filter_1_on":         1,
+	}
+	if len(preset.Settings) != len(wantSettings) {
+		t.Errorf("settings = %v, want %v", preset.Settings, wantSettings)
+	}
+	for setting, want := range wantSettings {
+		if got, ok := preset.Settings[setting]; !ok || math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", setting, got, want)
+		}
+	}
+	for _, off := range []string{"filter_2_on", "reverb_on", "distortion_on"} {
+		if _, ok := preset.Settings[off]; ok {
+			t.Errorf("%s is set, but none of the module's settings were", off)
+		}
+	}
+
+	var defaulted []string
+	for _, p := range report.Defaulted {
+		defaulted = append(defaulted, strconv.Itoa(p.Step)+" "+p.Parameter+": "+p.Reason)
+	}
+	wantDefaulted := []string{
+		"2 Filter 2 Cutoff: frequency must be above 0 Hz",
+		`2 Reverb Mix: value is in "ms", expected "%"`,
+		"2 Wobble: no matching setting in the preset format",
+		"5 Distortion Drive: flagged for review",
+	}
+	if fmt.Sprint(defaulted) != fmt.Sprint(wantDefaulted) {
+		t.Errorf("defaulted =\n%q\nwant\n%q", defaulted, wantDefaulted)
+	}
+
+	var unmapped []string
+	for _, s := range report.Unmapped {
+		unmapped = append(unmapped, strconv.Itoa(s.Step)+": "+s.Reason)
+	}
+	wantUnmapped := []string{
+		"4: has no parameter values",
+		"5: none of its parameters map to the preset",
+		"6: uses OTT, not Vital",
+		"7: doesn't use a device",
+	}
+	if fmt.Sprint(unmapped) != fmt.Sprint(wantUnmapped) {
+		t.Errorf("unmapped =\n%q\nwant\n%q", unmapped, wantUnmapped)
+	}
+
+	if len(report.Set) != 6 {
+		t.Errorf("report.Set = %+v, want 6 parameters", report.Set)
+	}
+}
+
+func TestVitalNothingToExport(t *testing.T) {
+	_, _, err := Vital(&models.Tutorial{Title: "Serum Bass", Instructions: []models.Instruction{
+		{StepNumber: 1, AbletonDevice: "Serum"},
+	}})
+	if !errors.Is(err, ErrNothingToExport) {
+		t.Errorf("err = %v, want ErrNothingToExport", err)
+	}
+}
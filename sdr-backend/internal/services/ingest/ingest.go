@@ -0,0 +1,330 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/database"
+	"github.com/camwick/sdr-backend/internal/services/jobs"
+	"github.com/camwick/sdr-backend/internal/services/source"
+)
+
+const (
+	// listTimeout bounds listing a profile's videos
+	listTimeout = 2 * time.Minute
+
+	// queueRetry is how long to wait before retrying when the job queue is full
+	queueRetry = 10 * time.Second
+
+	// ingestTimeout bounds how long an ingest may run; videos not finished by
+	// then are rejected
+	ingestTimeout = 6 * time.Hour
+
+	// retention is how long finished ingests stay queryable
+	retention = 24 * time.Hour
+)
+
+var (
+	// ErrInProgress is returned when the creator is already being ingested
+	ErrInProgress = errors.New("ingest already in progress")
+
+	// ErrNotFound is returned when the creator hasn't been ingested
+	ErrNotFound = errors.New("ingest not found")
+)
+
+// Service ingests every video on a creator's TikTok profile through the job
+// queue, running at most a fixed number of one creator's videos at a time
+// so a large profile doesn't take over the workers
+type Service struct {
+	tiktok      *source.TikTokSource
+	jobs        *jobs.Service
+	db          database.Repository
+	maxVideos   int
+	concurrency int
+
+	mu      sync.RWMutex
+	ingests map[string]*models.Ingest // by handle, latest only
+}
+
+// NewService creates an ingest service that lists up to maxVideos of a
+// profile and runs up to concurrency of a creator's videos at once
+func NewService(tiktok *source.TikTokSource, jobSvc *jobs.Service, repo database.Repository, maxVideos, concurrency int) *Service {
+	return &Service{
+		tiktok:      tiktok,
+		jobs:        jobSvc,
+		db:          repo,
+		maxVideos:   maxVideos,
+		concurrency: concurrency,
+		ingests:     make(map[string]*models.Ingest),
+	}
+}
+
+// MaxVideos is the most videos Start lists from a profile
+func (s *Service) MaxVideos() int {
+	return s.maxVideos
+}
+
+// Start lists up to limit of the creator's videos, skips the ones already
+// transcribed and queues the rest in the background. It returns the
+// ingest as it stands once the videos are listed.
+func (s *Service) Start(ctx context.Context, handle string, limit int) (*models.Ingest, error) {
+	s.prune()
+
+	s.mu.Lock()
+	if existing, ok := s.ingests[handle]; ok && !existing.Done {
+		s.mu.Unlock()
+		return nil, ErrInProgress
+	}
+	// Claim the handle while listing so concurrent requests get ErrInProgress
+	ingest := &models.Ingest{Handle: handle, StartedAt: time.Now().UTC()}
+	previous := s.ingests[handle]
+	s.ingests[handle] = ingest
+	s.mu.Unlock()
+
+	videos, err := s.list(ctx, handle, limit)
+	if err != nil {
+		s.mu.Lock()
+		if previous != nil {
+			s.ingests[handle] = previous
+		} else {
+			delete(s.ingests, handle)
+		}
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	s.mu.Lock()
+	ingest.Videos = videos
+	count(ingest)
+	if ingest.Pending == 0 {
+		finish(ingest)
+	}
+	snapshot := copyIngest(ingest)
+	s.mu.Unlock()
+
+	if !snapshot.Done {
+		go func() {
+			runCtx, cancel := context.WithTimeout(context.Background(), ingestTimeout)
+			defer cancel()
+			s.run(runCtx, ingest)
+		}()
+	}
+
+	return snapshot, nil
+}
+
+// Get returns the creator's latest ingest
+func (s *Service) Get(handle string) (*models.Ingest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ingest, ok := s.ingests[handle]
+	if !ok || ingest.Videos == nil {
+		return nil, ErrNotFound
+	}
+	return copyIngest(ingest), nil
+}
+
+// list fetches the profile's videos and marks the ones already transcribed
+func (s *Service) list(ctx context.Context, handle string, limit int) ([]models.IngestVideo, error) {
+	listCtx, cancel := context.WithTimeout(ctx, listTimeout)
+	defer cancel()
+
+	profileVideos, err := s.tiktok.ProfileVideos(listCtx, handle, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	videos := make([]models.IngestVideo, 0, len(profileVideos))
+	for _, pv := range profileVideos {
+		video := models.IngestVideo{
+			VideoID: pv.VideoID,
+			URL:     pv.URL,
+			Title:   pv.Title,
+			Status:  models.IngestVideoPending,
+		}
+
+		existing, err := s.db.GetTutorialByVideoID(ctx, source.TikTok, pv.VideoID)
+		if err != nil {
+			// The pipeline checks again before downloading
+			log.Printf("Database error checking existing: %v", err)
+		}
+		if existing != nil {
+			video.Status = models.IngestVideoSkipped
+			video.TutorialID = existing.ID
+		}
+
+		videos = append(videos, video)
+	}
+
+	return videos, nil
+}
+
+// run processes the pending videos, at most s.concurrency at a time, until
+// ctx is done. Videos not started by then are rejected.
+func (s *Service) run(ctx context.Context, ingest *models.Ingest) {
+	log.Printf("Ingesting @%s: %d videos, %d to transcribe", ingest.Handle, ingest.Total, ingest.Pending)
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, s.concurrency)
+videos:
+	for i := range ingest.Videos {
+		if ingest.Videos[i].Status != models.IngestVideoPending {
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			break videos
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			s.process(ctx, ingest, i)
+		}(i)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	for i := range ingest.Videos {
+		if ingest.Videos[i].Status == models.IngestVideoPending {
+			ingest.Videos[i].Status = models.IngestVideoRejected
+			ingest.Videos[i].Error = "Ingest timed out before the video was queued"
+		}
+	}
+	count(ingest)
+	finish(ingest)
+	log.Printf("Ingested @%s: %d accepted, %d not sound design, %d rejected, %d skipped",
+		ingest.Handle, ingest.Accepted, ingest.NotSoundDesign, ingest.Rejected, ingest.Skipped)
+	s.mu.Unlock()
+}
+
+// process runs one video through the job queue and records the outcome
+func (s *Service) process(ctx context.Context, ingest *models.Ingest, i int) {
+	url := ingest.Videos[i].URL // not modified after Start
+
+	job, err := s.enqueue(ctx, url)
+	if err != nil {
+		s.update(ingest, i, func(v *models.IngestVideo) {
+			v.Status = models.IngestVideoRejected
+			v.Error = "Failed to queue transcription"
+			if ctx.Err() != nil {
+				v.Error = "Ingest timed out before the video was queued"
+			}
+		})
+		return
+	}
+	s.update(ingest, i, func(v *models.IngestVideo) {
+		v.Status = models.IngestVideoProcessing
+		v.JobID = job.ID
+	})
+
+	job, err = s.jobs.Wait(ctx, job.ID)
+	s.update(ingest, i, func(v *models.IngestVideo) {
+		switch {
+		case ctx.Err() != nil:
+			v.Status = models.IngestVideoRejected
+			v.Error = "Ingest timed out before transcription finished"
+		case err != nil:
+			v.Status = models.IngestVideoRejected
+			v.Error = "Transcription job was lost"
+		case job.Stage == models.JobStageCompleted && job.Duplicate:
+			// Saved by another request since the profile was listed
+			v.Status = models.IngestVideoSkipped
+			v.TutorialID = job.TutorialID
+		case job.Stage == models.JobStageCompleted:
+			v.Status = models.IngestVideoAccepted
+			v.TutorialID = job.TutorialID
+		case job.ErrorCode == models.JobErrorNotSoundDesign:
+			v.Status = models.IngestVideoNotSoundDesign
+		default:
+			v.Status = models.IngestVideoRejected
+			v.Error = job.Error
+		}
+	})
+}
+
+// enqueue queues the URL, waiting for room while the job queue is full
+// until ctx is done
+func (s *Service) enqueue(ctx context.Context, url string) (*models.Job, error) {
+	for {
+		job, err := s.jobs.Enqueue(url)
+		if !errors.Is(err, jobs.ErrQueueFull) {
+			return job, err
+		}
+
+		timer := time.NewTimer(queueRetry)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// update applies fn to a video and recounts the ingest
+func (s *Service) update(ingest *models.Ingest, i int, fn func(*models.IngestVideo)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(&ingest.Videos[i])
+	count(ingest)
+}
+
+// count recomputes the ingest's totals from its videos
+func count(ingest *models.Ingest) {
+	ingest.Total = len(ingest.Videos)
+	ingest.Skipped, ingest.Pending, ingest.Processing = 0, 0, 0
+	ingest.Accepted, ingest.NotSoundDesign, ingest.Rejected = 0, 0, 0
+
+	for _, video := range ingest.Videos {
+		switch video.Status {
+		case models.IngestVideoSkipped:
+			ingest.Skipped++
+		case models.IngestVideoPending:
+			ingest.Pending++
+		case models.IngestVideoProcessing:
+			ingest.Processing++
+		case models.IngestVideoAccepted:
+			ingest.Accepted++
+		case models.IngestVideoNotSoundDesign:
+			ingest.NotSoundDesign++
+		case models.IngestVideoRejected:
+			ingest.Rejected++
+		}
+	}
+}
+
+// prune drops finished ingests older than the retention window
+func (s *Service) prune() {
+	cutoff := time.Now().UTC().Add(-retention)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for handle, ingest := range s.ingests {
+		if ingest.Done && ingest.FinishedAt.Before(cutoff) {
+			delete(s.ingests, handle)
+		}
+	}
+}
+
+func finish(ingest *models.Ingest) {
+	now := time.Now().UTC()
+	ingest.Done = true
+	ingest.FinishedAt = &now
+}
+
+func copyIngest(ingest *models.Ingest) *models.Ingest {
+	snapshot := *ingest
+	snapshot.Videos = make([]models.IngestVideo, len(ingest.Videos))
+	copy(snapshot.Videos, ingest.Videos)
+	return &snapshot
+}
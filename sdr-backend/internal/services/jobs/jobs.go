@@ -0,0 +1,364 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+const (
+	// jobTimeout bounds how long a single job may run
+	jobTimeout = 15 * time.Minute
+
+	// retention is how long finished jobs stay queryable
+	retention = time.Hour
+
+	// subscriberBuffer is how many events a slow subscriber may fall behind
+	// before it is disconnected
+	subscriberBuffer = 64
+)
+
+var (
+	// ErrQueueFull is returned when no more jobs can be accepted
+	ErrQueueFull = errors.New("job queue is full")
+
+	// ErrNotFound is returned for unknown or expired job IDs
+	ErrNotFound = errors.New("job not found")
+)
+
+// Result is the outcome of a successfully processed job
+type Result struct {
+	TutorialID string
+	Duplicate  bool
+}
+
+// RunFunc processes a single job, reporting progress through the tracker
+type RunFunc func(ctx context.Context, job models.Job, tracker *Tracker) (*Result, error)
+
+// Failure is a job error with a message that is safe to show to clients.
+// The wrapped error is only logged.
+type Failure struct {
+	Code    string // optional, see models.Job.ErrorCode
+	Message string
+	Err     error
+}
+
+func (f *Failure) Error() string {
+	if f.Err == nil {
+		return f.Message
+	}
+	return f.Message + ": " + f.Err.Error()
+}
+
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+// Fail wraps err with a client-facing message
+func Fail(message string, err error) error {
+	return &Failure{Message: message, Err: err}
+}
+
+// FailWithCode wraps err with a client-facing message and a code clients
+// can match on
+func FailWithCode(code, message string, err error) error {
+	return &Failure{Code: code, Message: message, Err: err}
+}
+
+// Service queues transcription jobs and runs them on a fixed pool of workers
+type Service struct {
+	run   RunFunc
+	queue chan string
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+}
+
+// entry is a job's state, event history and live subscribers
+type entry struct {
+	job         models.Job
+	events      []models.JobEvent
+	subscribers map[chan models.JobEvent]struct{}
+}
+
+// NewService creates a job service and starts its workers
+func NewService(workers, queueSize int, run RunFunc) *Service {
+	s := &Service{
+		run:     run,
+		queue:   make(chan string, queueSize),
+		entries: make(map[string]*entry),
+	}
+
+	for i := 0; i < workers; i++ {
+		go s.worker()
+	}
+
+	return s
+}
+
+// Enqueue registers a new job for the URL and schedules it
+func (s *Service) Enqueue(url string) (*models.Job, error) {
+	return s.enqueue(models.Job{URL: url})
+}
+
+// EnqueueUpload registers a new job for an uploaded file and schedules it.
+// The run function owns the file from here on, unless ErrQueueFull is
+// returned.
+func (s *Service) EnqueueUpload(upload *models.JobUpload) (*models.Job, error) {
+	return s.enqueue(models.Job{Upload: upload})
+}
+
+func (s *Service) enqueue(job models.Job) (*models.Job, error) {
+	s.prune()
+
+	now := time.Now().UTC()
+	job.ID = newID()
+	job.Stage = models.JobStageQueued
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	e := &entry{
+		job:         job,
+		subscribers: make(map[chan models.JobEvent]struct{}),
+	}
+	id := e.job.ID
+
+	s.mu.Lock()
+	s.entries[id] = e
+	s.mu.Unlock()
+
+	select {
+	case s.queue <- id:
+	default:
+		s.mu.Lock()
+		delete(s.entries, id)
+		s.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+
+	return s.Get(id)
+}
+
+// Get returns a snapshot of the job's current state
+func (s *Service) Get(id string) (*models.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	job := e.job
+	return &job, nil
+}
+
+// Subscribe returns the events emitted so far and a channel of events that
+// follow. The channel is closed after the job's terminal event, or early if
+// the subscriber falls too far behind. Call cancel to stop listening.
+func (s *Service) Subscribe(id string) (history []models.JobEvent, events <-chan models.JobEvent, cancel func(), err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		return nil, nil, nil, ErrNotFound
+	}
+
+	history = append([]models.JobEvent(nil), e.events...)
+	ch := make(chan models.JobEvent, subscriberBuffer)
+
+	if e.job.Stage.Done() {
+		close(ch)
+		return history, ch, func() {}, nil
+	}
+
+	e.subscribers[ch] = struct{}{}
+	cancel = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := e.subscribers[ch]; ok {
+			delete(e.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return history, ch, cancel, nil
+}
+
+// Wait blocks until the job finishes, or ctx is done, and returns its final
+// state
+func (s *Service) Wait(ctx context.Context, id string) (*models.Job, error) {
+	for {
+		_, events, cancel, err := s.Subscribe(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for open := true; open; {
+			select {
+			case _, open = <-events:
+			case <-ctx.Done():
+				cancel()
+				return nil, ctx.Err()
+			}
+		}
+		cancel()
+
+		// The channel also closes when a subscriber falls behind
+		job, err := s.Get(id)
+		if err != nil || job.Stage.Done() {
+			return job, err
+		}
+	}
+}
+
+func (s *Service) worker() {
+	for id := range s.queue {
+		s.process(id)
+	}
+}
+
+func (s *Service) process(id string) {
+	job, err := s.Get(id)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+	defer cancel()
+
+	result, err := s.run(ctx, *job, &Tracker{service: s, id: id})
+	if err != nil {
+		log.Printf("Job %s failed: %v", id, err)
+
+		message, code := "Transcription failed", ""
+		var failure *Failure
+		if errors.As(err, &failure) {
+			message, code = failure.Message, failure.Code
+		}
+
+		s.emit(id, func(j *models.Job) []models.JobEvent {
+			stage := j.Stage
+			j.Stage = models.JobStageFailed
+			j.Error = message
+			j.ErrorCode = code
+
+			events := []models.JobEvent{{Type: models.JobEventFailed, Stage: stage, Message: message}}
+			if stage != models.JobStageQueued {
+				events = append([]models.JobEvent{{Type: models.JobEventStageFailed, Stage: stage, Message: message}}, events...)
+			}
+			return events
+		})
+		return
+	}
+
+	log.Printf("Job %s completed: tutorial=%s duplicate=%v", id, result.TutorialID, result.Duplicate)
+
+	message := "Tutorial transcribed and saved successfully"
+	if result.Duplicate {
+		message = "Tutorial already exists"
+	}
+
+	s.emit(id, func(j *models.Job) []models.JobEvent {
+		j.Stage = models.JobStageCompleted
+		j.TutorialID = result.TutorialID
+		j.Duplicate = result.Duplicate
+
+		return []models.JobEvent{{
+			Type:    models.JobEventCompleted,
+			Message: message,
+			Data: map[string]interface{}{
+				"tutorial_id": result.TutorialID,
+				"duplicate":   result.Duplicate,
+			},
+		}}
+	})
+}
+
+// emit applies fn to the job and publishes the events it returns
+func (s *Service) emit(id string, fn func(*models.Job) []models.JobEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	events := fn(&e.job)
+	e.job.UpdatedAt = now
+
+	for _, event := range events {
+		event.ID = len(e.events) + 1
+		event.Time = now
+		e.events = append(e.events, event)
+
+		for ch := range e.subscribers {
+			select {
+			case ch <- event:
+			default:
+				// Too far behind; the client can reconnect and replay history
+				delete(e.subscribers, ch)
+				close(ch)
+			}
+		}
+	}
+
+	if e.job.Stage.Done() {
+		for ch := range e.subscribers {
+			delete(e.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// prune drops finished jobs older than the retention window
+func (s *Service) prune() {
+	cutoff := time.Now().UTC().Add(-retention)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, e := range s.entries {
+		if e.job.Stage.Done() && e.job.UpdatedAt.Before(cutoff) {
+			delete(s.entries, id)
+		}
+	}
+}
+
+// Tracker lets a running job report the progress of its stages
+type Tracker struct {
+	service *Service
+	id      string
+}
+
+// Start records that the job has moved on to stage
+func (t *Tracker) Start(stage models.JobStage, message string) {
+	log.Printf("Job %s: %s", t.id, message)
+	t.service.emit(t.id, func(j *models.Job) []models.JobEvent {
+		j.Stage = stage
+		return []models.JobEvent{{Type: models.JobEventStageStarted, Stage: stage, Message: message}}
+	})
+}
+
+// Complete records that stage finished, with any details worth showing
+func (t *Tracker) Complete(stage models.JobStage, message string, data map[string]interface{}) {
+	log.Printf("Job %s: %s", t.id, message)
+	t.service.emit(t.id, func(j *models.Job) []models.JobEvent {
+		return []models.JobEvent{{Type: models.JobEventStageCompleted, Stage: stage, Message: message, Data: data}}
+	})
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
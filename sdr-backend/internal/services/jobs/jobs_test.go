@@ -0,0 +1,318 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// waitDone polls until the job has finished
+func waitDone(t *testing.T, s *Service, id string) *models.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := s.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Stage.Done() {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s didn't finish", id)
+	return nil
+}
+
+// eventTypes lists the types of events, checking their IDs count up from 1
+func eventTypes(t *testing.T, events []models.JobEvent) []models.JobEventType {
+	t.Helper()
+
+	var types []models.JobEventType
+	for i, event := range events {
+		if event.ID != i+1 {
+			t.Errorf("event %d has ID %d", i, event.ID)
+		}
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestRunToCompletion(t *testing.T) {
+	s := NewService(1, 1, func(ctx context.Context, job models.Job, tracker *Tracker) (*Result, error) {
+		tracker.Start(models.JobStageExtracting, "Extracting audio...")
+		tracker.Complete(models.JobStageExtracting, "Extracted", map[string]interface{}{"video_id": "1"})
+		return &Result{TutorialID: "tutorial-1"}, nil
+	})
+
+	job, err := s.Enqueue("https://www.tiktok.com/@producer/video/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID == "" {
+		t.Errorf("enqueued job = %+v", job)
+	}
+
+	done := waitDone(t, s, job.ID)
+	if done.Stage != models.JobStageCompleted || done.TutorialID != "tutorial-1" || done.Duplicate {
+		t.Errorf("finished job = %+v", done)
+	}
+
+	history, _, cancel, err := s.Subscribe(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	want := []models.JobEventType{models.JobEventStageStarted, models.JobEventStageCompleted, models.JobEventCompleted}
+	if got := eventTypes(t, history); !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	if last := history[len(history)-1]; last.Data["tutorial_id"] != "tutorial-1" {
+		t.Errorf("completed event = %+v", last)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		started    bool
+		err        error
+		want       string
+		wantCode   string
+		wantEvents []models.JobEventType
+	}{
+		{
+			name:       "failure in a stage",
+			started:    true,
+			err:        Fail("Failed to transcribe audio", errors.New("exit status 1")),
+			want:       "Failed to transcribe audio",
+			wantEvents: []models.JobEventType{models.JobEventStageStarted, models.JobEventStageFailed, models.JobEventFailed},
+		},
+		{
+			name:       "wrapped failure before any stage",
+			err:        errors.Join(errors.New("context"), Fail("Unsupported video URL", nil)),
+			want:       "Unsupported video URL",
+			wantEvents: []models.JobEventType{models.JobEventFailed},
+		},
+		{
+			name:       "failure with a code",
+			started:    true,
+			err:        FailWithCode(models.JobErrorNotSoundDesign, "Not a tutorial", nil),
+			want:       "Not a tutorial",
+			wantCode:   models.JobErrorNotSoundDesign,
+			wantEvents: []models.JobEventType{models.JobEventStageStarted, models.JobEventStageFailed, models.JobEventFailed},
+		},
+		{
+			name:       "internal error",
+			err:        errors.New("connection refused"),
+			want:       "Transcription failed",
+			wantEvents: []models.JobEventType{models.JobEventFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(1, 1, func(ctx context.Context, job models.Job, tracker *Tracker) (*Result, error) {
+				if tt.started {
+					tracker.Start(models.JobStageTranscribing, "Transcribing audio...")
+				}
+				return nil, tt.err
+			})
+			job, err := s.Enqueue("https://www.tiktok.com/@producer/video/1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			done := waitDone(t, s, job.ID)
+			if done.Stage != models.JobStageFailed || done.Error != tt.want || done.ErrorCode != tt.wantCode {
+				t.Errorf("finished job = %+v, want error %q with code %q", done, tt.want, tt.wantCode)
+			}
+
+			history, _, cancel, err := s.Subscribe(job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cancel()
+			if got := eventTypes(t, history); !slices.Equal(got, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", got, tt.wantEvents)
+			}
+			if last := history[len(history)-1]; last.Message != tt.want {
+				t.Errorf("failed event = %+v", last)
+			}
+		})
+	}
+}
+
+func TestJobTimeout(t *testing.T) {
+	deadlines := make(chan time.Duration, 1)
+	s := NewService(1, 1, func(ctx context.Context, job models.Job, tracker *Tracker) (*Result, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			deadlines <- 0
+		} else {
+			deadlines <- time.Until(deadline)
+		}
+		return &Result{}, nil
+	})
+	if _, err := s.Enqueue("https://www.tiktok.com/@producer/video/1"); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining := <-deadlines
+	if remaining <= jobTimeout-time.Minute || remaining > jobTimeout {
+		t.Errorf("job ran with %s left, want about %s", remaining, jobTimeout)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	// No workers, so nothing leaves the queue
+	s := NewService(0, 1, func(ctx context.Context, job models.Job, tracker *Tracker) (*Result, error) {
+		return &Result{}, nil
+	})
+
+	first, err := s.Enqueue("https://www.tiktok.com/@producer/video/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Enqueue("https://www.tiktok.com/@producer/video/2"); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("err = %v, want ErrQueueFull", err)
+	}
+
+	if _, err := s.Get(first.ID); err != nil {
+		t.Errorf("queued job: %v", err)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.entries) != 1 {
+		t.Errorf("%d jobs registered, want only the queued one", len(s.entries))
+	}
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := NewService(1, 1, func(ctx context.Context, job models.Job, tracker *Tracker) (*Result, error) {
+		tracker.Start(models.JobStageExtracting, "Extracting audio...")
+		close(started)
+		<-release
+		tracker.Complete(models.JobStageExtracting, "Extracted", nil)
+		return &Result{TutorialID: "tutorial-1"}, nil
+	})
+
+	job, err := s.Enqueue("https://www.tiktok.com/@producer/video/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// Joining mid-run replays what was missed, then follows live
+	history, events, cancel, err := s.Subscribe(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	if got := eventTypes(t, history); !slices.Equal(got, []models.JobEventType{models.JobEventStageStarted}) {
+		t.Errorf("history = %v", got)
+	}
+
+	close(release)
+	var live []models.JobEvent
+	for event := range events {
+		live = append(live, event)
+	}
+	all := eventTypes(t, append(history, live...))
+	want := []models.JobEventType{models.JobEventStageStarted, models.JobEventStageCompleted, models.JobEventCompleted}
+	if !slices.Equal(all, want) {
+		t.Errorf("events = %v, want %v", all, want)
+	}
+
+	// Subscribing after the job finished replays everything and ends
+	history, events, cancel, err = s.Subscribe(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	if _, open := <-events; open {
+		t.Error("events channel is open for a finished job")
+	}
+	if got := eventTypes(t, history); !slices.Equal(got, want) {
+		t.Errorf("replay = %v, want %v", got, want)
+	}
+
+	if _, _, _, err := s.Subscribe("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown job: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWait(t *testing.T) {
+	release := make(chan struct{})
+	s := NewService(1, 2, func(ctx context.Context, job models.Job, tracker *Tracker) (*Result, error) {
+		tracker.Start(models.JobStageExtracting, "Extracting audio...")
+		<-release
+		return &Result{TutorialID: "tutorial-" + job.URL}, nil
+	})
+
+	job, err := s.Enqueue("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Gives up when ctx is done, without affecting the job
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Wait(ctx, job.ID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want DeadlineExceeded", err)
+	}
+
+	close(release)
+	done, err := s.Wait(context.Background(), job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Stage != models.JobStageCompleted || done.TutorialID != "tutorial-1" {
+		t.Errorf("Wait = %+v", done)
+	}
+
+	// Returns at once for a job that already finished
+	if again, err := s.Wait(context.Background(), job.ID); err != nil || again.TutorialID != "tutorial-1" {
+		t.Errorf("second Wait = %+v, %v", again, err)
+	}
+
+	if _, err := s.Wait(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown job: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := NewService(0, 4, nil)
+	old := time.Now().UTC().Add(-retention - time.Minute)
+	for id, job := range map[string]models.Job{
+		"finished":      {Stage: models.JobStageCompleted, UpdatedAt: old},
+		"failed":        {Stage: models.JobStageFailed, UpdatedAt: old},
+		"recent":        {Stage: models.JobStageCompleted, UpdatedAt: time.Now().UTC()},
+		"still running": {Stage: models.JobStageTranscribing, UpdatedAt: old},
+		"still queued":  {Stage: models.JobStageQueued, UpdatedAt: old},
+	} {
+		job.ID = id
+		s.entries[id] = &entry{job: job}
+	}
+
+	s.prune()
+
+	for _, id := range []string{"finished", "failed"} {
+		if _, err := s.Get(id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: err = %v, want ErrNotFound", id, err)
+		}
+	}
+	for _, id := range []string{"recent", "still running", "still queued"} {
+		if _, err := s.Get(id); err != nil {
+			t.Errorf("%s was pruned: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+const (
+	anthropicBaseURL      = "https://api.anthropic.com/v1"
+	anthropicDefaultModel = "claude-sonnet-4-20250514"
+)
+
+// Anthropic parses transcriptions with Claude through the Messages API
+type Anthropic struct {
+	apiKey    string
+	baseURL   string
+	model     string
+	maxTokens int
+	client    *http.Client
+}
+
+var _ RecipeParser = (*Anthropic)(nil)
+
+// NewAnthropic creates a Claude parser. An empty baseURL or model uses the
+// public API and the default model.
+func NewAnthropic(apiKey, baseURL, model string, maxTokens int) *Anthropic {
+	if baseURL == "" {
+		baseURL = anthropicBaseURL
+	}
+	if model == "" {
+		model = anthropicDefaultModel
+	}
+	return &Anthropic{
+		apiKey:    apiKey,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		model:     model,
+		maxTokens: maxTokens,
+		client:    &http.Client{},
+	}
+}
+
+type claudeRequest struct {
+	Model      string            `json:"model"`
+	MaxTokens  int               `json:"max_tokens"`
+	Messages   []claudeMessage   `json:"messages"`
+	Tools      []claudeTool      `json:"tools,omitempty"`
+	ToolChoice *claudeToolChoice `json:"tool_choice,omitempty"`
+}
+
+type claudeTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+type claudeToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type claudeMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type claudeResponse struct {
+	Content []struct {
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		Name  string          `json:"name"`  // tool_use blocks
+		Input json.RawMessage `json:"input"` // tool_use blocks
+	} `json:"content"`
+}
+
+// Parse takes a raw transcription and extracts structured sound design instructions
+func (s *Anthropic) Parse(ctx context.Context, req Request) (*models.ParsedRecipe, error) {
+	prompt := buildPrompt(req)
+
+	reqBody := claudeRequest{
+		Model:     s.model,
+		MaxTokens: s.maxTokens,
+		Messages: []claudeMessage{
+			{Role: "user", Content: prompt},
+		},
+		Tools: []claudeTool{{
+			Name:        recipeToolName,
+			Description: recipeToolDescription,
+			InputSchema: recipeSchema(),
+		}},
+		ToolChoice: &claudeToolChoice{Type: "tool", Name: recipeToolName},
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/messages", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", s.apiKey)
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("claude API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var claudeResp claudeResponse
+	if err := json.Unmarshal(body, &claudeResp); err != nil {
+		return nil, fmt.Errorf("failed to parse claude response: %w", err)
+	}
+
+	if len(claudeResp.Content) == 0 {
+		return nil, fmt.Errorf("empty response from claude")
+	}
+
+	// Prefer the tool call; keep any text as a fallback
+	var toolInput []byte
+	var text strings.Builder
+	for _, block := range claudeResp.Content {
+		switch {
+		case block.Type == "tool_use" && block.Name == recipeToolName:
+			toolInput = block.Input
+		case block.Type == "text":
+			text.WriteString(block.Text)
+		}
+	}
+
+	return decodeRecipe(toolInput, text.String(), req.Segments)
+}
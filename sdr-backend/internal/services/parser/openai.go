@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+const (
+	openAIBaseURL      = "https://api.openai.com/v1"
+	openAIDefaultModel = "gpt-4o-mini"
+)
+
+// OpenAI parses transcriptions through an OpenAI-compatible Chat Completions
+// API. Pointing baseURL at a local Ollama (http://localhost:11434/v1) or
+// llama.cpp server runs the parser offline.
+type OpenAI struct {
+	apiKey    string
+	baseURL   string
+	model     string
+	maxTokens int
+	client    *http.Client
+}
+
+var _ RecipeParser = (*OpenAI)(nil)
+
+// NewOpenAI creates an OpenAI-compatible parser. The API key may be empty
+// for local servers; an empty baseURL or model uses OpenAI and its default
+// model.
+func NewOpenAI(apiKey, baseURL, model string, maxTokens int) *OpenAI {
+	if baseURL == "" {
+		baseURL = openAIBaseURL
+	}
+	if model == "" {
+		model = openAIDefaultModel
+	}
+	return &OpenAI{
+		apiKey:    apiKey,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		model:     model,
+		maxTokens: maxTokens,
+		client:    &http.Client{},
+	}
+}
+
+type openAIRequest struct {
+	Model      string            `json:"model"`
+	MaxTokens  int               `json:"max_tokens"`
+	Messages   []openAIMessage   `json:"messages"`
+	Tools      []openAITool      `json:"tools,omitempty"`
+	ToolChoice *openAIToolChoice `json:"tool_choice,omitempty"`
+}
+
+type openAIMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []openAIToolCall `json:"tool_calls,omitempty"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Arguments   string                 `json:"arguments,omitempty"` // JSON-encoded, in responses
+}
+
+type openAIToolChoice struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIToolCall struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Parse takes a raw transcription and extracts structured sound design instructions
+func (s *OpenAI) Parse(ctx context.Context, req Request) (*models.ParsedRecipe, error) {
+	prompt := buildPrompt(req)
+
+	reqBody := openAIRequest{
+		Model:     s.model,
+		MaxTokens: s.maxTokens,
+		Messages: []openAIMessage{
+			{Role: "user", Content: prompt},
+		},
+		Tools: []openAITool{{
+			Type: "function",
+			Function: openAIFunction{
+				Name:        recipeToolName,
+				Description: recipeToolDescription,
+				Parameters:  recipeSchema(),
+			},
+		}},
+		ToolChoice: &openAIToolChoice{
+			Type:     "function",
+			Function: openAIFunction{Name: recipeToolName},
+		},
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var openAIResp openAIResponse
+	if err := json.Unmarshal(body, &openAIResp); err != nil {
+		return nil, fmt.Errorf("failed to parse openai response: %w", err)
+	}
+
+	if len(openAIResp.Choices) == 0 {
+		return nil, fmt.Errorf("empty response from openai")
+	}
+
+	// Prefer the function call; keep any text as a fallback
+	message := openAIResp.Choices[0].Message
+	var toolInput []byte
+	for _, call := range message.ToolCalls {
+		if call.Function.Name == recipeToolName {
+			toolInput = []byte(call.Function.Arguments)
+		}
+	}
+
+	return decodeRecipe(toolInput, message.Content, req.Segments)
+}
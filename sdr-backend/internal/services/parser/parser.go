@@ -1,119 +1,131 @@
 package parser
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"strings"
 
 	"github.com/camwick/sdr-backend/internal/models"
 )
 
-const claudeAPIURL = "https://api.anthropic.com/v1/messages"
-
-// Service handles parsing transcriptions into structured recipes
-type Service struct {
-	apiKey string
-	client *http.Client
+// RecipeParser turns a tutorial transcription into structured sound design
+// instructions using an LLM
+type RecipeParser interface {
+	// Parse extracts a recipe from the transcription. When timestamped
+	// segments are available, each instruction is linked to the part of the
+	// video where it's explained.
+	Parse(ctx context.Context, req Request) (*models.ParsedRecipe, error)
 }
 
-// NewService creates a new parser service
-func NewService(apiKey string) *Service {
-	return &Service{
-		apiKey: apiKey,
-		client: &http.Client{},
-	}
-}
+// Request is the input to a RecipeParser
+type Request struct {
+	Transcription string
+	Segments      []models.TranscriptSegment
+	CreatorName   string
 
-type claudeRequest struct {
-	Model     string          `json:"model"`
-	MaxTokens int             `json:"max_tokens"`
-	Messages  []claudeMessage `json:"messages"`
+	// Problems with a previous answer, sent back so the model can fix them
+	Feedback []string
 }
 
-type claudeMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+// ResponseError is returned when the model's answer contains no usable
+// recipe, neither as a tool call nor as JSON in its text
+type ResponseError struct {
+	Response string // raw model output, for logging
+	Err      error
 }
 
-type claudeResponse struct {
-	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
-	} `json:"content"`
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("model returned no usable recipe: %v (response: %s)", e.Err, e.Response)
 }
 
-// Parse takes a raw transcription and extracts structured sound design instructions
-func (s *Service) Parse(transcription string, creatorName string) (*models.ParsedRecipe, error) {
-	prompt := buildPrompt(transcription, creatorName)
-
-	reqBody := claudeRequest{
-		Model:     "claude-sonnet-4-20250514",
-		MaxTokens: 2048,
-		Messages: []claudeMessage{
-			{Role: "user", Content: prompt},
-		},
-	}
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
 
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
+// decodeRecipe reads the recipe from the tool call arguments, falling back
+// to the first JSON object in the model's text, and aligns its timestamps
+// with the transcript
+func decodeRecipe(toolInput []byte, text string, segments []models.TranscriptSegment) (*models.ParsedRecipe, error) {
+	var recipe models.ParsedRecipe
 
-	req, err := http.NewRequest("POST", claudeAPIURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	err := errors.New("no tool call or JSON object in response")
+	if len(toolInput) > 0 {
+		if err = json.Unmarshal(toolInput, &recipe); err == nil {
+			alignTimestamps(recipe.Instructions, segments)
+			return &recipe, nil
+		}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", s.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+	if object, ok := extractJSONObject(text); ok {
+		if err = json.Unmarshal([]byte(object), &recipe); err == nil {
+			alignTimestamps(recipe.Instructions, segments)
+			return &recipe, nil
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	response := text
+	if response == "" {
+		response = string(toolInput)
 	}
+	return nil, &ResponseError{Response: response, Err: err}
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("claude API error (status %d): %s", resp.StatusCode, string(body))
+// extractJSONObject returns the first balanced {...} in text, which is how
+// models usually answer when they wrap JSON in markdown or prose
+func extractJSONObject(text string) (string, bool) {
+	start := strings.IndexByte(text, '{')
+	if start < 0 {
+		return "", false
 	}
 
-	var claudeResp claudeResponse
-	if err := json.Unmarshal(body, &claudeResp); err != nil {
-		return nil, fmt.Errorf("failed to parse claude response: %w", err)
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return text[start : i+1], true
+			}
+		}
 	}
+	return "", false
+}
 
-	if len(claudeResp.Content) == 0 {
-		return nil, fmt.Errorf("empty response from claude")
+func buildPrompt(req Request) string {
+	transcription := req.Transcription
+	timing := "- Omit start_time and end_time, no timestamps are available for this video"
+	if len(req.Segments) > 0 {
+		transcription = formatSegments(req.Segments)
+		timing = "- Set start_time and end_time to the seconds (from the [start-end] markers) where each step is explained"
 	}
 
-	// Extract JSON from response
-	responseText := claudeResp.Content[0].Text
-	
-	var recipe models.ParsedRecipe
-	if err := json.Unmarshal([]byte(responseText), &recipe); err != nil {
-		return nil, fmt.Errorf("failed to parse recipe JSON: %w (response: %s)", err, responseText)
+	feedback := ""
+	if len(req.Feedback) > 0 {
+		feedback = "\n\nYour previous answer for this transcription had these problems. Fix them in your new answer:\n- " +
+			strings.Join(req.Feedback, "\n- ")
 	}
 
-	return &recipe, nil
-}
-
-func buildPrompt(transcription string, creatorName string) string {
 	return fmt.Sprintf(`You are an expert at analyzing sound design tutorials for Ableton Live. 
 
-Analyze the following transcription from a TikTok video by %s and extract structured sound design instructions.
+Analyze the following transcription from a short-form video by %s and extract structured sound design instructions.
 
 TRANSCRIPTION:
 %s
 
-Respond with ONLY valid JSON (no markdown, no explanation) in this exact format:
+Record the recipe by calling the `+recipeToolName+` tool. If you can't call tools, respond with ONLY valid JSON (no markdown, no explanation) in this exact format:
 {
   "title": "Short descriptive title for this sound (e.g., 'Massive Reese Bass', 'Plucky Arp')",
   "sound_type": "Category of sound (e.g., 'bass', 'lead', 'pad', 'arp', 'kick', 'snare', 'fx', 'chord')",
@@ -125,7 +137,9 @@ Respond with ONLY valid JSON (no markdown, no explanation) in this exact format:
       "description": "Clear instruction of what to do",
       "ableton_device": "Name of Ableton device if mentioned (e.g., 'Wavetable', 'Operator', 'Serum', 'Saturator')",
       "parameters": {"param_name": "value"},
-      "notes": "Any additional tips or context"
+      "notes": "Any additional tips or context",
+      "start_time": 12.5,
+      "end_time": 18.0
     }
   ]
 }
@@ -135,5 +149,52 @@ Rules:
 - Extract specific parameter values when mentioned (frequencies, percentages, knob positions)
 - Identify the Ableton device or VST being used for each step
 - Keep descriptions clear and actionable
-- Include any tips or warnings mentioned by the creator`, creatorName, transcription)
+- Include any tips or warnings mentioned by the creator
+- sound_type must be one of: %s
+%s%s`, req.CreatorName, transcription, strings.Join(SoundTypes, ", "), timing, feedback)
+}
+
+// formatSegments renders the transcription one segment per line, prefixed
+// with its time range in seconds
+func formatSegments(segments []models.TranscriptSegment) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		fmt.Fprintf(&b, "[%.1f-%.1f] %s\n", seg.StartTime, seg.EndTime, seg.Text)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// alignTimestamps snaps each instruction's time range to the boundaries of
+// the segments it falls in, so it always points at real speech. Times are
+// dropped when there are no segments to check them against.
+func alignTimestamps(instructions []models.ParsedInstruction, segments []models.TranscriptSegment) {
+	for i := range instructions {
+		inst := &instructions[i]
+		if len(segments) == 0 || inst.StartTime == nil {
+			inst.StartTime, inst.EndTime = nil, nil
+			continue
+		}
+
+		end := *inst.StartTime
+		if inst.EndTime != nil && *inst.EndTime > end {
+			end = *inst.EndTime
+		}
+
+		start := segmentAt(segments, *inst.StartTime).StartTime
+		end = segmentAt(segments, end).EndTime
+		inst.StartTime, inst.EndTime = &start, &end
+	}
+}
+
+// segmentAt returns the segment being spoken at t, clamping to the first or
+// last segment when t is out of range
+func segmentAt(segments []models.TranscriptSegment, t float64) models.TranscriptSegment {
+	found := segments[0]
+	for _, seg := range segments {
+		if seg.StartTime > t {
+			break
+		}
+		found = seg
+	}
+	return found
 }
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+func TestExtractJSONObject(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string // empty when there's no object
+	}{
+		{"bare", `{"title":"Reese"}`, `{"title":"Reese"}`},
+		{"markdown fence", "Here you go:\n```json\n{\"title\":\"Reese\"}\n```", `{"title":"Reese"}`},
+		{"prose after", `{"a":1} hope that helps {"b":2}`, `{"a":1}`},
+		{"nested", `x {"a":{"b":{"c":1}}} y`, `{"a":{"b":{"c":1}}}`},
+		{"brace in string", `{"notes":"use } and { freely"}`, `{"notes":"use } and { freely"}`},
+		{"escaped quote in string", `{"notes":"say \"}\" twice"}`, `{"notes":"say \"}\" twice"}`},
+		{"escaped backslash before quote", `{"path":"C:\\"} tail`, `{"path":"C:\\"}`},
+		{"no object", "I couldn't find a recipe", ""},
+		{"unbalanced", `{"title":"Reese"`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := extractJSONObject(tt.text)
+			if ok != (tt.want != "") || got != tt.want {
+				t.Errorf("extractJSONObject(%q) = %q, %v, want %q", tt.text, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRecipe(t *testing.T) {
+	t.Run("tool input", func(t *testing.T) {
+		recipe, err := decodeRecipe([]byte(`{"title":"Reese","is_sound_design":true}`), "ignored", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recipe.Title != "Reese" || !recipe.IsSoundDesign {
+			t.Errorf("recipe = %+v", recipe)
+		}
+	})
+
+	t.Run("falls back to text", func(t *testing.T) {
+		recipe, err := decodeRecipe([]byte(`not json`), "```json\n{\"title\":\"Pluck\"}\n```", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recipe.Title != "Pluck" {
+			t.Errorf("Title = %q, want Pluck", recipe.Title)
+		}
+	})
+
+	t.Run("non-string parameter values", func(t *testing.T) {
+		input := `{"title":"Reese","instructions":[{"step_number":1,"description":"Open the filter",` +
+			`"parameters":{"Cutoff":"800 Hz","Resonance":40,"Drive":-6.5,"Soft Clip":true,"Mode":null}}]}`
+		recipe, err := decodeRecipe([]byte(input), "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := models.ParsedParameters{"Cutoff": "800 Hz", "Resonance": "40", "Drive": "-6.5", "Soft Clip": "true"}
+		if got := recipe.Instructions[0].Parameters; !maps.Equal(got, want) {
+			t.Errorf("Parameters = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("no recipe", func(t *testing.T) {
+		_, err := decodeRecipe(nil, "no idea", nil)
+		var responseErr *ResponseError
+		if !errors.As(err, &responseErr) {
+			t.Fatalf("err = %v, want a ResponseError", err)
+		}
+		if responseErr.Response != "no idea" {
+			t.Errorf("Response = %q, want the model's text", responseErr.Response)
+		}
+	})
+}
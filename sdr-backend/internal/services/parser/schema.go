@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+const (
+	recipeToolName        = "save_recipe"
+	recipeToolDescription = "Save the sound design recipe extracted from the transcription"
+)
+
+// recipeSchema is the JSON schema for models.ParsedRecipe, used as the
+// input schema of the tool the model is asked to call
+var recipeSchema = sync.OnceValue(func() map[string]interface{} {
+	return schemaFor(reflect.TypeOf(models.ParsedRecipe{}))
+})
+
+// schemaFor builds a JSON schema for t from its json and desc struct tags.
+// Fields without omitempty are required.
+func schemaFor(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaFor(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			property := schemaFor(field.Type)
+			if desc := field.Tag.Get("desc"); desc != "" {
+				property["description"] = desc
+			}
+			properties[name] = property
+
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+	default:
+		return map[string]interface{}{}
+	}
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// SoundTypes is the controlled vocabulary for ParsedRecipe.SoundType
+var SoundTypes = []string{
+	"bass", "lead", "pad", "pluck", "arp", "keys", "chord", "vocal",
+	"kick", "snare", "hihat", "percussion", "fx", "texture",
+}
+
+const (
+	// maxParameterKey and maxParameterValue bound parameter lengths; anything
+	// longer is a sentence, which belongs in the description or notes
+	maxParameterKey   = 64
+	maxParameterValue = 64
+)
+
+// ValidationError is returned when a recipe still fails validation after
+// the model was asked to fix it
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "recipe failed validation: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks a parsed recipe for problems the schema can't express and
+// returns them as sentences the model can act on. SoundType is lowercased
+// in place before it's checked.
+func Validate(recipe *models.ParsedRecipe) []string {
+	var problems []string
+
+	// Nothing else matters for videos that will be rejected anyway
+	if !recipe.IsSoundDesign {
+		return nil
+	}
+
+	if strings.TrimSpace(recipe.Title) == "" {
+		problems = append(problems, "title is empty")
+	}
+
+	recipe.SoundType = strings.ToLower(strings.TrimSpace(recipe.SoundType))
+	if !isSoundType(recipe.SoundType) {
+		problems = append(problems, fmt.Sprintf("sound_type %q is not one of: %s", recipe.SoundType, strings.Join(SoundTypes, ", ")))
+	}
+
+	if len(recipe.Instructions) == 0 {
+		problems = append(problems, "is_sound_design is true but there are no instructions")
+	}
+
+	for i, inst := range recipe.Instructions {
+		step := fmt.Sprintf("instruction %d", i+1)
+
+		if inst.StepNumber != i+1 {
+			problems = append(problems, fmt.Sprintf("%s has step_number %d, step numbers must run 1, 2, 3... in order with no gaps or repeats", step, inst.StepNumber))
+		}
+
+		if strings.TrimSpace(inst.Description) == "" {
+			problems = append(problems, step+" has an empty description")
+		}
+
+		for key, value := range inst.Parameters {
+			switch {
+			case strings.TrimSpace(key) == "":
+				problems = append(problems, step+" has a parameter with an empty name")
+			case strings.TrimSpace(value) == "":
+				problems = append(problems, fmt.Sprintf("%s parameter %q has an empty value", step, key))
+			case len(key) > maxParameterKey || len(value) > maxParameterValue || strings.ContainsAny(key+value, "\n\r"):
+				problems = append(problems, fmt.Sprintf("%s parameter %q should be a short name and value (e.g. \"Cutoff\": \"800 Hz\"), put explanations in notes", step, key))
+			}
+		}
+
+		if inst.StartTime != nil && inst.EndTime != nil && *inst.EndTime < *inst.StartTime {
+			problems = append(problems, step+" ends before it starts")
+		}
+	}
+
+	return problems
+}
+
+func isSoundType(soundType string) bool {
+	for _, t := range SoundTypes {
+		if t == soundType {
+			return true
+		}
+	}
+	return false
+}
+
+// Validated checks the wrapped parser's recipes and, when they have
+// problems or no recipe could be read, asks the model once more with the
+// problems listed
+type Validated struct {
+	next RecipeParser
+}
+
+var _ RecipeParser = (*Validated)(nil)
+
+// NewValidated wraps next with validation and a single repair attempt
+func NewValidated(next RecipeParser) *Validated {
+	return &Validated{next: next}
+}
+
+// Parse parses and validates the recipe, re-prompting once on failure
+func (s *Validated) Parse(ctx context.Context, req Request) (*models.ParsedRecipe, error) {
+	recipe, problems, err := s.attempt(ctx, req)
+	if err != nil || len(problems) == 0 {
+		return recipe, err
+	}
+
+	log.Printf("Parsed recipe has %d problems, asking the model to fix them: %s", len(problems), strings.Join(problems, "; "))
+
+	req.Feedback = problems
+	recipe, problems, err = s.attempt(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return recipe, nil
+}
+
+// attempt runs one parse. A response with no readable recipe is reported
+// as a problem so it gets the same second chance as an invalid one.
+func (s *Validated) attempt(ctx context.Context, req Request) (*models.ParsedRecipe, []string, error) {
+	recipe, err := s.next.Parse(ctx, req)
+
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) && len(req.Feedback) == 0 {
+		return nil, []string{"the answer did not contain a recipe, call the " + recipeToolName + " tool with the recipe"}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return recipe, Validate(recipe), nil
+}
@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+func validRecipe() *models.ParsedRecipe {
+	return &models.ParsedRecipe{
+		Title:         "Reese Bass",
+		SoundType:     "bass",
+		IsSoundDesign: true,
+		Instructions: []models.ParsedInstruction{
+			{StepNumber: 1, Description: "Load Wavetable", AbletonDevice: "Wavetable"},
+			{StepNumber: 2, Description: "Detune", Parameters: map[string]string{"Detune": "30%"}},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	start, end := 12.0, 8.0
+
+	tests := []struct {
+		name   string
+		modify func(*models.ParsedRecipe)
+		want   []string // substrings, one per expected problem
+	}{
+		{"valid", func(r *models.ParsedRecipe) {}, nil},
+		{"not sound design", func(r *models.ParsedRecipe) {
+			*r = models.ParsedRecipe{IsSoundDesign: false}
+		}, nil},
+		{"sound type is lowercased", func(r *models.ParsedRecipe) { r.SoundType = " Bass " }, nil},
+		{"empty title", func(r *models.ParsedRecipe) { r.Title = "  " }, []string{"title is empty"}},
+		{"unknown sound type", func(r *models.ParsedRecipe) { r.SoundType = "wobble" }, []string{`sound_type "wobble"`}},
+		{"no instructions", func(r *models.ParsedRecipe) { r.Instructions = nil }, []string{"no instructions"}},
+		{"step numbers", func(r *models.ParsedRecipe) { r.Instructions[1].StepNumber = 1 }, []string{"instruction 2 has step_number 1"}},
+		{"empty description", func(r *models.ParsedRecipe) { r.Instructions[0].Description = "" }, []string{"instruction 1 has an empty description"}},
+		{"empty parameter name", func(r *models.ParsedRecipe) {
+			r.Instructions[1].Parameters = map[string]string{" ": "30%"}
+		}, []string{"empty name"}},
+		{"empty parameter value", func(r *models.ParsedRecipe) {
+			r.Instructions[1].Parameters = map[string]string{"Detune": ""}
+		}, []string{`"Detune" has an empty value`}},
+		{"sentence as parameter", func(r *models.ParsedRecipe) {
+			r.Instructions[1].Parameters = map[string]string{"Detune": strings.Repeat("turn it up a lot ", 5)}
+		}, []string{"should be a short name and value"}},
+		{"multi-line parameter", func(r *models.ParsedRecipe) {
+			r.Instructions[1].Parameters = map[string]string{"Detune": "30%\nthen more"}
+		}, []string{"should be a short name and value"}},
+		{"ends before it starts", func(r *models.ParsedRecipe) {
+			r.Instructions[0].StartTime, r.Instructions[0].EndTime = &start, &end
+		}, []string{"instruction 1 ends before it starts"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := validRecipe()
+			tt.modify(recipe)
+
+			problems := Validate(recipe)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("problems = %q, want %d", problems, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %d = %q, want it to mention %q", i, problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+// fakeParser returns its answers in order and records the requests
+type fakeParser struct {
+	answers  []*models.ParsedRecipe
+	errs     []error
+	requests []Request
+}
+
+func (f *fakeParser) Parse(ctx context.Context, req Request) (*models.ParsedRecipe, error) {
+	i := len(f.requests)
+	f.requests = append(f.requests, req)
+	return f.answers[i], f.errs[i]
+}
+
+func TestValidatedRepromptsOnce(t *testing.T) {
+	invalid := validRecipe()
+	invalid.Title = ""
+
+	t.Run("fixed on second attempt", func(t *testing.T) {
+		fake := &fakeParser{answers: []*models.ParsedRecipe{invalid, validRecipe()}, errs: []error{nil, nil}}
+
+		recipe, err := NewValidated(fake).Parse(context.Background(), Request{Transcription: "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recipe.Title != "Reese Bass" {
+			t.Errorf("Title = %q, want the second answer", recipe.Title)
+		}
+		if len(fake.requests) != 2 || len(fake.requests[1].Feedback) != 1 {
+			t.Errorf("requests = %+v, want a second request with the problem as feedback", fake.requests)
+		}
+	})
+
+	t.Run("still invalid", func(t *testing.T) {
+		fake := &fakeParser{answers: []*models.ParsedRecipe{invalid, invalid}, errs: []error{nil, nil}}
+
+		_, err := NewValidated(fake).Parse(context.Background(), Request{})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("err = %v, want a ValidationError", err)
+		}
+	})
+
+	t.Run("no recipe in first answer", func(t *testing.T) {
+		fake := &fakeParser{
+			answers: []*models.ParsedRecipe{nil, validRecipe()},
+			errs:    []error{&ResponseError{Response: "hm", Err: errors.New("no json")}, nil},
+		}
+
+		if _, err := NewValidated(fake).Parse(context.Background(), Request{}); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.requests) != 2 {
+			t.Errorf("made %d requests, want 2", len(fake.requests))
+		}
+	})
+
+	t.Run("valid first time", func(t *testing.T) {
+		fake := &fakeParser{answers: []*models.ParsedRecipe{validRecipe()}, errs: []error{nil}}
+
+		if _, err := NewValidated(fake).Parse(context.Background(), Request{}); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.requests) != 1 {
+			t.Errorf("made %d requests, want 1", len(fake.requests))
+		}
+	})
+}
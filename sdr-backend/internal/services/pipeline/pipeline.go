@@ -0,0 +1,322 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/catalog"
+	"github.com/camwick/sdr-backend/internal/services/database"
+	"github.com/camwick/sdr-backend/internal/services/jobs"
+	"github.com/camwick/sdr-backend/internal/services/parser"
+	"github.com/camwick/sdr-backend/internal/services/source"
+	"github.com/camwick/sdr-backend/internal/services/transcription"
+	"github.com/camwick/sdr-backend/internal/services/units"
+)
+
+// ErrNotSoundDesign is returned when the parsed video isn't a sound design tutorial
+var ErrNotSoundDesign = errors.New("not a sound design tutorial")
+
+const (
+	// saveAttempts is how many times a transiently failing save is tried
+	saveAttempts = 3
+
+	// saveBackoff is the delay before the first retry, doubled after each attempt
+	saveBackoff = 500 * time.Millisecond
+)
+
+// Service runs the transcription pipeline: URL -> audio -> transcription -> parsing -> save
+type Service struct {
+	sources       *source.Registry
+	uploads       *source.Uploads
+	transcription transcription.Transcriber
+	parser        parser.RecipeParser
+	catalog       *catalog.Catalog
+	db            database.Repository
+}
+
+// NewService creates a new pipeline service
+func NewService(
+	sources *source.Registry,
+	uploads *source.Uploads,
+	transcriber transcription.Transcriber,
+	recipeParser parser.RecipeParser,
+	deviceCatalog *catalog.Catalog,
+	repo database.Repository,
+) *Service {
+	return &Service{
+		sources:       sources,
+		uploads:       uploads,
+		transcription: transcriber,
+		parser:        recipeParser,
+		catalog:       deviceCatalog,
+		db:            repo,
+	}
+}
+
+// Run processes a transcription job; it satisfies jobs.RunFunc
+func (s *Service) Run(ctx context.Context, job models.Job, tracker *jobs.Tracker) (*jobs.Result, error) {
+	// Check if already transcribed, before spending a download on it
+	existing, resolved := s.findExisting(ctx, job)
+	if existing != nil {
+		log.Printf("Tutorial already exists: %s", existing.ID)
+		if job.Upload != nil {
+			s.uploads.Cleanup(job.Upload.FileID)
+		}
+		return &jobs.Result{TutorialID: existing.ID, Duplicate: true}, nil
+	}
+
+	// Step 1: Extract audio from the video or uploaded file
+	tracker.Start(models.JobStageExtracting, "Step 1: Extracting audio...")
+	videoInfo, cleanup, err := s.extractAudio(ctx, job)
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	tracker.Complete(models.JobStageExtracting,
+		fmt.Sprintf("Extracted video: ID=%s, Creator=%s", videoInfo.VideoID, videoInfo.CreatorHandle),
+		map[string]interface{}{
+			"platform":       videoInfo.Platform,
+			"video_id":       videoInfo.VideoID,
+			"creator_handle": videoInfo.CreatorHandle,
+			"creator_name":   videoInfo.CreatorName,
+		})
+
+	// Videos that couldn't be checked up front are checked now
+	if !resolved {
+		existing, err := s.db.GetTutorialByVideoID(ctx, videoInfo.Platform, videoInfo.VideoID)
+		if err != nil {
+			log.Printf("Database error checking existing: %v", err)
+		}
+		if existing != nil {
+			log.Printf("Tutorial already exists: %s", existing.ID)
+			return &jobs.Result{TutorialID: existing.ID, Duplicate: true}, nil
+		}
+	}
+
+	// Step 2: Transcribe audio
+	tracker.Start(models.JobStageTranscribing, "Step 2: Transcribing audio...")
+	transcriptionResult, err := s.transcription.Transcribe(ctx, videoInfo.AudioPath)
+	if err != nil {
+		return nil, jobs.Fail("Failed to transcribe audio", err)
+	}
+
+	segments := toSegments(transcriptionResult.Segments)
+
+	tracker.Complete(models.JobStageTranscribing,
+		fmt.Sprintf("Transcription complete: %d characters, %d segments", len(transcriptionResult.Text), len(segments)),
+		map[string]interface{}{
+			"characters": len(transcriptionResult.Text),
+			"segments":   len(segments),
+		})
+
+	// Step 3: Parse with Claude
+	tracker.Start(models.JobStageParsing, "Step 3: Parsing transcription...")
+	recipe, err := s.parser.Parse(ctx, parser.Request{
+		Transcription: transcriptionResult.Text,
+		Segments:      segments,
+		CreatorName:   videoInfo.CreatorName,
+	})
+	var responseErr *parser.ResponseError
+	if errors.As(err, &responseErr) {
+		return nil, jobs.Fail("The model didn't return a usable recipe", err)
+	}
+	var validationErr *parser.ValidationError
+	if errors.As(err, &validationErr) {
+		return nil, jobs.Fail("The parsed recipe failed validation", err)
+	}
+	if err != nil {
+		return nil, jobs.Fail("Failed to parse transcription", err)
+	}
+
+	// Map device names onto the catalog so recipes can be grouped by device
+	for i := range recipe.Instructions {
+		if device := recipe.Instructions[i].AbletonDevice; device != "" {
+			recipe.Instructions[i].AbletonDevice = s.catalog.Normalize(device)
+		}
+	}
+
+	tracker.Complete(models.JobStageParsing,
+		fmt.Sprintf("Parsed recipe: Title=%s, SoundType=%s, IsSoundDesign=%v",
+			recipe.Title, recipe.SoundType, recipe.IsSoundDesign),
+		map[string]interface{}{
+			"title":           recipe.Title,
+			"sound_type":      recipe.SoundType,
+			"is_sound_design": recipe.IsSoundDesign,
+			"instructions":    len(recipe.Instructions),
+		})
+
+	// Check if it's actually sound design content
+	if !recipe.IsSoundDesign {
+		return nil, jobs.FailWithCode(models.JobErrorNotSoundDesign,
+			"This video doesn't appear to be a sound design tutorial", ErrNotSoundDesign)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, jobs.Fail("Transcription timed out", err)
+	}
+
+	// Step 4: Save to database
+	tracker.Start(models.JobStageSaving, "Step 4: Saving to database...")
+
+	// Get or create creator
+	creator, err := s.db.GetOrCreateCreator(ctx, videoInfo.Platform, videoInfo.CreatorHandle, videoInfo.CreatorName)
+	if err != nil {
+		return nil, jobs.Fail("Failed to save creator", err)
+	}
+
+	// Create tutorial with its instructions
+	tutorial := &models.Tutorial{
+		CreatorID:        creator.ID,
+		Platform:         videoInfo.Platform,
+		SourceURL:        job.URL, // empty for uploads
+		VideoID:          videoInfo.VideoID,
+		Title:            recipe.Title,
+		SoundType:        recipe.SoundType,
+		RawTranscription: transcriptionResult.Text,
+		Status:           "pending",
+		Instructions:     s.toInstructions(recipe.Instructions),
+		Segments:         segments,
+	}
+
+	savedTutorial, err := s.saveTutorial(ctx, tutorial)
+	if errors.Is(err, database.ErrDuplicate) {
+		// Saved concurrently, or by an attempt whose response was lost
+		existing, lookupErr := s.db.GetTutorialByVideoID(ctx, tutorial.Platform, tutorial.VideoID)
+		if lookupErr != nil {
+			return nil, jobs.Fail("Failed to save tutorial", lookupErr)
+		}
+		if existing == nil {
+			return nil, jobs.Fail("Failed to save tutorial", err)
+		}
+		log.Printf("Tutorial already exists: %s", existing.ID)
+		return &jobs.Result{TutorialID: existing.ID, Duplicate: true}, nil
+	}
+	if err != nil {
+		return nil, jobs.Fail("Failed to save tutorial", err)
+	}
+
+	tracker.Complete(models.JobStageSaving,
+		fmt.Sprintf("Tutorial saved successfully: ID=%s", savedTutorial.ID),
+		map[string]interface{}{"tutorial_id": savedTutorial.ID})
+
+	return &jobs.Result{TutorialID: savedTutorial.ID}, nil
+}
+
+// findExisting looks for a tutorial already saved from the job's video,
+// resolving short links to the video ID without downloading anything.
+// resolved is false if the ID couldn't be found this way or the lookup
+// failed, in which case the check is repeated after the download.
+func (s *Service) findExisting(ctx context.Context, job models.Job) (existing *models.Tutorial, resolved bool) {
+	platform, videoID := source.Upload, ""
+	if job.Upload != nil {
+		videoID = job.Upload.Hash
+	} else {
+		src, err := s.sources.ForURL(job.URL)
+		if err != nil {
+			return nil, false
+		}
+		videoID, err = src.ResolveID(ctx, job.URL)
+		if err != nil {
+			log.Printf("Could not resolve video ID for %s before downloading: %v", job.URL, err)
+			return nil, false
+		}
+		platform = src.Platform()
+	}
+
+	existing, err := s.db.GetTutorialByVideoID(ctx, platform, videoID)
+	if err != nil {
+		log.Printf("Database error checking existing: %v", err)
+		return nil, false
+	}
+	return existing, true
+}
+
+// extractAudio produces the job's audio from its URL or uploaded file. The
+// returned cleanup removes the job's temp files and is never nil.
+func (s *Service) extractAudio(ctx context.Context, job models.Job) (*source.VideoInfo, func(), error) {
+	if job.Upload != nil {
+		log.Printf("Processing upload: %s (%s)", job.Upload.FileName, job.Upload.FileID)
+
+		cleanup := func() { s.uploads.Cleanup(job.Upload.FileID) }
+		videoInfo, err := s.uploads.ExtractAudio(ctx, job.Upload)
+		if err != nil {
+			return nil, cleanup, jobs.Fail("Failed to convert the uploaded file", err)
+		}
+		return videoInfo, cleanup, nil
+	}
+
+	log.Printf("Processing video URL: %s", job.URL)
+
+	src, err := s.sources.ForURL(job.URL)
+	if err != nil {
+		return nil, func() {}, jobs.Fail("Unsupported video URL", err)
+	}
+
+	videoInfo, err := src.ExtractAudio(ctx, job.URL)
+	if err != nil {
+		return nil, func() {}, jobs.Fail("Failed to extract audio from the video", err)
+	}
+	return videoInfo, func() { src.Cleanup(videoInfo) }, nil
+}
+
+// saveTutorial saves the tutorial and its instructions as one unit, retrying
+// the whole unit when the repository reports a transient failure
+func (s *Service) saveTutorial(ctx context.Context, tutorial *models.Tutorial) (*models.Tutorial, error) {
+	backoff := saveBackoff
+	for attempt := 1; ; attempt++ {
+		saved, err := s.db.SaveTutorial(ctx, tutorial)
+		if err == nil || !errors.Is(err, database.ErrTransient) || attempt == saveAttempts {
+			return saved, err
+		}
+
+		log.Printf("Save attempt %d/%d failed, retrying in %s: %v", attempt, saveAttempts, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// toInstructions types the parsed parameter values and maps their names
+// onto the device's parameter schema
+func (s *Service) toInstructions(parsed []models.ParsedInstruction) []models.Instruction {
+	instructions := make([]models.Instruction, 0, len(parsed))
+	for _, inst := range parsed {
+		instructions = append(instructions, models.Instruction{
+			StepNumber:    inst.StepNumber,
+			Description:   inst.Description,
+			AbletonDevice: inst.AbletonDevice,
+			Parameters:    s.catalog.NormalizeParameters(inst.AbletonDevice, units.Normalize(inst.Parameters)),
+			Notes:         inst.Notes,
+			StartTime:     inst.StartTime,
+			EndTime:       inst.EndTime,
+		})
+	}
+	return instructions
+}
+
+func toSegments(transcribed []transcription.Segment) []models.TranscriptSegment {
+	segments := make([]models.TranscriptSegment, 0, len(transcribed))
+	for _, seg := range transcribed {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		segments = append(segments, models.TranscriptSegment{
+			SegmentIndex: len(segments),
+			StartTime:    seg.Start,
+			EndTime:      seg.End,
+			Text:         text,
+		})
+	}
+	return segments
+}
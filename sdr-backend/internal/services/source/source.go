@@ -0,0 +1,121 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// Platforms, as stored in tutorials.platform
+const (
+	TikTok    = "tiktok"
+	YouTube   = "youtube"
+	Instagram = "instagram"
+	Upload    = "upload" // files posted to /api/transcribe/upload
+)
+
+// ErrUnsupported is returned for URLs no source accepts
+var ErrUnsupported = errors.New("unsupported video URL")
+
+// VideoInfo is what a source knows about a video
+type VideoInfo struct {
+	Platform      string
+	VideoID       string
+	CreatorName   string
+	CreatorHandle string
+	Title         string
+	AudioPath     string // set by ExtractAudio
+}
+
+// Source is a video platform tutorials can be ingested from
+type Source interface {
+	// Platform names the source, e.g. "tiktok"
+	Platform() string
+
+	// ValidateURL reports whether url is a video on this platform
+	ValidateURL(url string) bool
+
+	// ResolveID returns the platform's canonical ID for the video at url
+	// without downloading it, following short links to the video they
+	// point at
+	ResolveID(ctx context.Context, url string) (string, error)
+
+	// Metadata fetches the video's ID, title and creator without
+	// downloading it
+	Metadata(ctx context.Context, url string) (*VideoInfo, error)
+
+	// ExtractAudio downloads the video and extracts its audio for
+	// transcription
+	ExtractAudio(ctx context.Context, url string) (*VideoInfo, error)
+
+	// Cleanup removes the files ExtractAudio left for the video it returned
+	Cleanup(video *VideoInfo)
+}
+
+// Registry picks the source for a URL
+type Registry struct {
+	sources []Source
+}
+
+// NewRegistry creates a registry that tries sources in order
+func NewRegistry(sources ...Source) *Registry {
+	return &Registry{sources: sources}
+}
+
+// Default returns a registry with TikTok, YouTube and Instagram, all
+// downloading through yt-dlp
+func Default() *Registry {
+	downloader := NewDownloader()
+	return NewRegistry(
+		NewTikTok(downloader),
+		NewYouTube(downloader),
+		NewInstagram(downloader),
+	)
+}
+
+// ForURL returns the source that accepts url
+func (r *Registry) ForURL(url string) (Source, error) {
+	for _, source := range r.sources {
+		if source.ValidateURL(url) {
+			return source, nil
+		}
+	}
+	return nil, ErrUnsupported
+}
+
+// TikTok returns the registered TikTok source, or nil
+func (r *Registry) TikTok() *TikTokSource {
+	for _, source := range r.sources {
+		if tiktok, ok := source.(*TikTokSource); ok {
+			return tiktok
+		}
+	}
+	return nil
+}
+
+// Platforms lists the registered platforms
+func (r *Registry) Platforms() []string {
+	platforms := make([]string, 0, len(r.sources))
+	for _, source := range r.sources {
+		platforms = append(platforms, source.Platform())
+	}
+	return platforms
+}
+
+// matchAny reports whether url matches one of patterns
+func matchAny(patterns []*regexp.Regexp, url string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// idFromURL returns the first capture group of idPattern in url
+func idFromURL(idPattern *regexp.Regexp, url string) (string, bool) {
+	if matches := idPattern.FindStringSubmatch(url); len(matches) > 1 {
+		return matches[1], true
+	}
+	return "", false
+}
@@ -0,0 +1,98 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegistryForURL(t *testing.T) {
+	registry := Default()
+
+	tests := []struct {
+		url  string
+		want string // platform, empty when unsupported
+	}{
+		{"https://www.tiktok.com/@creator/video/7234567890123456789", TikTok},
+		{"https://m.tiktok.com/@creator.name/video/7234567890123456789?lang=en", TikTok},
+		{"https://vm.tiktok.com/ZMabc123/", TikTok},
+		{"https://vt.tiktok.com/ZSabc123/", TikTok},
+		{"https://www.tiktok.com/t/ZTabc123/", TikTok},
+		{"https://www.youtube.com/shorts/dQw4w9WgXcQ", YouTube},
+		{"https://youtube.com/watch?v=dQw4w9WgXcQ", YouTube},
+		{"https://www.youtube.com/watch?feature=share&v=dQw4w9WgXcQ", YouTube},
+		{"https://music.youtube.com/watch?v=dQw4w9WgXcQ", YouTube},
+		{"https://youtu.be/dQw4w9WgXcQ?si=abc", YouTube},
+		{"https://www.instagram.com/reel/C1a2b3c4d5e/", Instagram},
+		{"https://instagram.com/p/C1a2b3c4d5e/", Instagram},
+		{"https://www.instagram.com/tv/C1a2b3c4d5e", Instagram},
+		{"https://www.tiktok.com/@creator", ""},
+		{"https://www.youtube.com/watch?v=short", ""},
+		{"https://www.youtube.com/@creator", ""},
+		{"https://www.instagram.com/creator/", ""},
+		{"https://example.com/video/123", ""},
+		{"not a url", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			src, err := registry.ForURL(tt.url)
+			if tt.want == "" {
+				if !errors.Is(err, ErrUnsupported) {
+					t.Errorf("ForURL = %v, %v, want ErrUnsupported", src, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if src.Platform() != tt.want {
+				t.Errorf("Platform = %q, want %q", src.Platform(), tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveIDFromURL(t *testing.T) {
+	registry := Default()
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.tiktok.com/@creator/video/7234567890123456789?is_from_webapp=1", "7234567890123456789"},
+		{"https://www.youtube.com/shorts/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/watch?feature=share&v=dQw4w9WgXcQ&t=10", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.instagram.com/reel/C1a2b3c4d5e/?igsh=abc", "C1a2b3c4d5e"},
+		{"https://www.instagram.com/p/C1a2b3-c_4d/", "C1a2b3-c_4d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			src, err := registry.ForURL(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// IDs in the URL are read without any network access
+			got, err := src.ResolveID(context.Background(), tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryPlatforms(t *testing.T) {
+	registry := Default()
+
+	if registry.TikTok() == nil {
+		t.Error("TikTok() = nil")
+	}
+	if got := registry.Platforms(); len(got) != 3 || got[0] != TikTok || got[1] != YouTube || got[2] != Instagram {
+		t.Errorf("Platforms = %v", got)
+	}
+}
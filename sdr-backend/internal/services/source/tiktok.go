@@ -0,0 +1,68 @@
+package source
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+var (
+	tiktokPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^https?://(www\.|m\.)?tiktok\.com/@[\w.-]+/video/\d+`),
+		regexp.MustCompile(`^https?://(vm|vt)\.tiktok\.com/\w+`),
+		regexp.MustCompile(`^https?://(www\.)?tiktok\.com/t/\w+`),
+	}
+	tiktokID = regexp.MustCompile(`/video/(\d+)`)
+)
+
+// TikTokSource ingests TikTok videos
+type TikTokSource struct {
+	site
+}
+
+var _ Source = (*TikTokSource)(nil)
+
+// NewTikTok creates the TikTok source
+func NewTikTok(downloader *Downloader) *TikTokSource {
+	return &TikTokSource{site{
+		platform:   TikTok,
+		downloader: downloader,
+		idPattern:  tiktokID,
+		shortLinks: true,
+		creator: func(info *ytdlpInfo) (string, string) {
+			handle := strings.TrimPrefix(firstNonEmpty(info.UploaderID, info.Uploader), "@")
+			return firstNonEmpty(info.Uploader, handle), handle
+		},
+	}}
+}
+
+// ValidateURL accepts tiktok.com video links and vm.tiktok.com short links
+func (s *TikTokSource) ValidateURL(url string) bool {
+	return matchAny(tiktokPatterns, url)
+}
+
+// ProfileVideo is a video listed on a creator's profile
+type ProfileVideo struct {
+	VideoID string
+	URL     string
+	Title   string
+}
+
+// ProfileVideos lists up to limit of the creator's most recent videos
+func (s *TikTokSource) ProfileVideos(ctx context.Context, handle string, limit int) ([]ProfileVideo, error) {
+	profileURL := "https://www.tiktok.com/@" + handle
+	entries, err := s.downloader.playlist(ctx, profileURL, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	videos := make([]ProfileVideo, 0, len(entries))
+	for _, entry := range entries {
+		videos = append(videos, ProfileVideo{
+			VideoID: entry.ID,
+			URL:     profileURL + "/video/" + entry.ID,
+			Title:   entry.Title,
+		})
+	}
+	return videos, nil
+}
@@ -0,0 +1,182 @@
+package source
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/camwick/sdr-backend/internal/models"
+	"github.com/camwick/sdr-backend/internal/services/transcription"
+)
+
+var (
+	// ErrTooLarge is returned by Save for files over the size limit
+	ErrTooLarge = errors.New("file too large")
+
+	// ErrTooLong is returned by Save for recordings over the duration limit
+	ErrTooLong = errors.New("recording too long")
+
+	// ErrNoAudio is returned by Save for files ffprobe can't read, that
+	// have no audio stream or whose length is unknown
+	ErrNoAudio = errors.New("not a readable video or audio file")
+)
+
+// Uploads keeps uploaded video and audio files until their job runs, and
+// converts them to the same mp3 the URL sources produce
+type Uploads struct {
+	tempDir     string
+	maxBytes    int64
+	maxDuration time.Duration
+}
+
+// NewUploads creates an upload store that accepts files up to maxBytes and
+// recordings up to maxDuration
+func NewUploads(maxBytes int64, maxDuration time.Duration) *Uploads {
+	tempDir := filepath.Join(os.TempDir(), "sdr-uploads")
+	os.MkdirAll(tempDir, 0755)
+	return &Uploads{tempDir: tempDir, maxBytes: maxBytes, maxDuration: maxDuration}
+}
+
+// MaxBytes is the largest file Save accepts
+func (u *Uploads) MaxBytes() int64 {
+	return u.maxBytes
+}
+
+// MaxDuration is the longest recording Save accepts
+func (u *Uploads) MaxDuration() time.Duration {
+	return u.maxDuration
+}
+
+// Save stores the file and checks it's a recording within the limits. The
+// recording is identified by a hash of its contents, so uploading the same
+// recording twice is caught as a duplicate, but each upload is stored under
+// its own file ID so concurrent jobs for the same recording don't share
+// files. The caller fills in the creator.
+func (u *Uploads) Save(ctx context.Context, r io.Reader, fileName string) (*models.JobUpload, error) {
+	tmp, err := os.CreateTemp(u.tempDir, "incoming-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, u.maxBytes+1))
+	tmp.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store upload: %w", err)
+	}
+	if written > u.maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	duration, err := probeRecording(ctx, tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	if time.Duration(duration*float64(time.Second)) > u.maxDuration {
+		return nil, ErrTooLong
+	}
+
+	contentHash := hex.EncodeToString(hash.Sum(nil))[:32]
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	fileID := contentHash + "-" + hex.EncodeToString(suffix)
+	if err := os.Rename(tmp.Name(), u.path(fileID, "orig")); err != nil {
+		return nil, fmt.Errorf("failed to store upload: %w", err)
+	}
+
+	return &models.JobUpload{
+		FileID:   fileID,
+		Hash:     contentHash,
+		FileName: filepath.Base(fileName),
+		Duration: duration,
+	}, nil
+}
+
+// ExtractAudio converts the uploaded file to mp3, as yt-dlp does for URLs
+func (u *Uploads) ExtractAudio(ctx context.Context, upload *models.JobUpload) (*VideoInfo, error) {
+	audioPath := u.path(upload.FileID, "mp3")
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-y", "-i", u.path(upload.FileID, "orig"),
+		"-vn",
+		"-c:a", "libmp3lame",
+		"-q:a", "0", // Best quality
+		audioPath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to convert upload: %w (%s)", err, transcription.LastLine(output))
+	}
+
+	return &VideoInfo{
+		Platform:      Upload,
+		VideoID:       upload.Hash,
+		CreatorName:   upload.CreatorName,
+		CreatorHandle: upload.CreatorHandle,
+		Title:         strings.TrimSuffix(upload.FileName, filepath.Ext(upload.FileName)),
+		AudioPath:     audioPath,
+	}, nil
+}
+
+// Cleanup removes the uploaded file and its converted audio
+func (u *Uploads) Cleanup(fileID string) {
+	files, _ := filepath.Glob(u.path(fileID, "*"))
+	for _, f := range files {
+		os.Remove(f)
+	}
+}
+
+func (u *Uploads) path(fileID, ext string) string {
+	return filepath.Join(u.tempDir, Upload+"-"+fileID+"."+ext)
+}
+
+// probeRecording returns the length in seconds of a file with an audio
+// stream
+func probeRecording(ctx context.Context, path string) (float64, error) {
+	output, err := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "a",
+		"-show_entries", "stream=codec_type:format=duration",
+		"-of", "json",
+		path,
+	).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return 0, ErrNoAudio
+		}
+		return 0, fmt.Errorf("failed to probe upload: %w", err)
+	}
+
+	var probe struct {
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return 0, fmt.Errorf("failed to parse upload probe: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return 0, ErrNoAudio
+	}
+
+	duration, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil {
+		return 0, ErrNoAudio
+	}
+	return duration, nil
+}
@@ -0,0 +1,42 @@
+package source
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	youtubePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^https?://(www\.|m\.)?youtube\.com/shorts/[\w-]{11}`),
+		regexp.MustCompile(`^https?://(www\.|m\.|music\.)?youtube\.com/watch\?(.*&)?v=[\w-]{11}`),
+		regexp.MustCompile(`^https?://youtu\.be/[\w-]{11}`),
+	}
+	youtubeID = regexp.MustCompile(`(?:/shorts/|[?&]v=|youtu\.be/)([\w-]{11})`)
+)
+
+// YouTubeSource ingests YouTube videos and Shorts
+type YouTubeSource struct {
+	site
+}
+
+var _ Source = (*YouTubeSource)(nil)
+
+// NewYouTube creates the YouTube source
+func NewYouTube(downloader *Downloader) *YouTubeSource {
+	return &YouTubeSource{site{
+		platform:   YouTube,
+		downloader: downloader,
+		idPattern:  youtubeID,
+		creator: func(info *ytdlpInfo) (string, string) {
+			// uploader_id is the @handle on current channels and the
+			// channel ID on ones without a handle
+			handle := strings.TrimPrefix(firstNonEmpty(info.UploaderID, info.ChannelID), "@")
+			return firstNonEmpty(info.Channel, info.Uploader, handle), handle
+		},
+	}}
+}
+
+// ValidateURL accepts Shorts, watch and youtu.be links
+func (s *YouTubeSource) ValidateURL(url string) bool {
+	return matchAny(youtubePatterns, url)
+}
@@ -0,0 +1,255 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// redirectTimeout bounds following a short link to its video
+const redirectTimeout = 10 * time.Second
+
+// errFound stops a redirect chain once the video ID is known
+var errFound = errors.New("video ID found")
+
+// Downloader fetches metadata and audio through yt-dlp, which every source
+// uses under the hood
+type Downloader struct {
+	tempDir string
+	client  *http.Client
+}
+
+// NewDownloader creates a downloader that keeps audio in a temp directory
+func NewDownloader() *Downloader {
+	tempDir := filepath.Join(os.TempDir(), "sdr-downloads")
+	os.MkdirAll(tempDir, 0755)
+	return &Downloader{
+		tempDir: tempDir,
+		client:  &http.Client{Timeout: redirectTimeout},
+	}
+}
+
+// ytdlpInfo is the part of yt-dlp's --dump-json output the sources read.
+// Which of the uploader and channel fields are set varies by site.
+type ytdlpInfo struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Uploader   string `json:"uploader"`
+	UploaderID string `json:"uploader_id"`
+	Channel    string `json:"channel"`
+	ChannelID  string `json:"channel_id"`
+}
+
+// info fetches a video's metadata without downloading it
+func (d *Downloader) info(ctx context.Context, url string) (*ytdlpInfo, error) {
+	output, err := exec.CommandContext(ctx, "yt-dlp",
+		"--dump-json",
+		"--no-download",
+		"--no-playlist",
+		url,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+
+	var info ytdlpInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse video info: %w", err)
+	}
+	if info.ID == "" {
+		return nil, fmt.Errorf("video info has no ID")
+	}
+
+	return &info, nil
+}
+
+// playlist lists up to limit entries of a playlist or profile without
+// fetching each video's metadata
+func (d *Downloader) playlist(ctx context.Context, url string, limit int) ([]ytdlpInfo, error) {
+	output, err := exec.CommandContext(ctx, "yt-dlp",
+		"--flat-playlist",
+		"--dump-json",
+		"--playlist-end", strconv.Itoa(limit),
+		url,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list videos: %w", err)
+	}
+
+	// One JSON object per line
+	var entries []ytdlpInfo
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for decoder.More() {
+		var entry ytdlpInfo
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("failed to parse video list: %w", err)
+		}
+		if entry.ID != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// redirectedID follows url's redirects until one lands on a URL idPattern
+// matches, without reading any response body
+func (d *Downloader) redirectedID(ctx context.Context, url string, idPattern *regexp.Regexp) (string, error) {
+	var id string
+	client := *d.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if match, ok := idFromURL(idPattern, req.URL.String()); ok {
+			id = match
+			return errFound
+		}
+		if len(via) >= 10 {
+			return errors.New("too many redirects")
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	// Short link services serve an interstitial page instead of a redirect
+	// to clients that don't look like a browser
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36")
+
+	resp, err := client.Do(req)
+	if id != "" {
+		return id, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to follow short link: %w", err)
+	}
+	resp.Body.Close()
+
+	return "", fmt.Errorf("short link redirected to %s, which has no video ID", resp.Request.URL)
+}
+
+// extract downloads url and converts its audio to mp3, returning the path.
+// Each download gets its own directory, so jobs for the same video don't
+// overwrite or clean up each other's files.
+func (d *Downloader) extract(ctx context.Context, platform, videoID, url string) (string, error) {
+	dir, err := os.MkdirTemp(d.tempDir, platform+"-"+videoID+"-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "yt-dlp",
+		"-x",                    // Extract audio
+		"--audio-format", "mp3", // Convert to mp3
+		"--audio-quality", "0", // Best quality
+		"--no-playlist",
+		"-o", filepath.Join(dir, "audio.%(ext)s"),
+		url,
+	)
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to download audio: %w", err)
+	}
+
+	return filepath.Join(dir, "audio.mp3"), nil
+}
+
+// cleanup removes the download directory holding audioPath
+func (d *Downloader) cleanup(audioPath string) {
+	dir := filepath.Dir(audioPath)
+	if filepath.Dir(dir) != d.tempDir {
+		return // not one of ours
+	}
+	os.RemoveAll(dir)
+}
+
+// site implements Source for a platform apart from URL validation. The
+// platform types supply where the video ID is in a URL, whether short
+// links can be followed to find it, and how creators are named.
+type site struct {
+	platform   string
+	downloader *Downloader
+	idPattern  *regexp.Regexp
+	shortLinks bool // short links redirect to a URL with the video ID
+	creator    func(info *ytdlpInfo) (name, handle string)
+}
+
+func (s *site) Platform() string {
+	return s.platform
+}
+
+// ResolveID reads the ID from the URL when it's there. Otherwise it follows
+// the short link's redirects, and as a last resort asks yt-dlp, which
+// fetches metadata but not the video.
+func (s *site) ResolveID(ctx context.Context, url string) (string, error) {
+	if id, ok := idFromURL(s.idPattern, url); ok {
+		return id, nil
+	}
+
+	if s.shortLinks {
+		id, err := s.downloader.redirectedID(ctx, url, s.idPattern)
+		if err == nil {
+			return id, nil
+		}
+		log.Printf("Falling back to yt-dlp to resolve %s: %v", url, err)
+	}
+
+	info, err := s.downloader.info(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return info.ID, nil
+}
+
+func (s *site) Metadata(ctx context.Context, url string) (*VideoInfo, error) {
+	info, err := s.downloader.info(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	name, handle := s.creator(info)
+	return &VideoInfo{
+		Platform:      s.platform,
+		VideoID:       info.ID,
+		CreatorName:   name,
+		CreatorHandle: handle,
+		Title:         info.Title,
+	}, nil
+}
+
+func (s *site) ExtractAudio(ctx context.Context, url string) (*VideoInfo, error) {
+	video, err := s.Metadata(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	video.AudioPath, err = s.downloader.extract(ctx, s.platform, video.VideoID, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return video, nil
+}
+
+func (s *site) Cleanup(video *VideoInfo) {
+	s.downloader.cleanup(video.AudioPath)
+}
+
+// firstNonEmpty returns the first value that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
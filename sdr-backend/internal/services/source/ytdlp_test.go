@@ -0,0 +1,97 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRedirectedID(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/t/one-hop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/@creator/video/7234567890123456789", http.StatusFound)
+	})
+	mux.HandleFunc("/t/two-hops", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/t/one-hop", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/@creator/video/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the video page was fetched, the redirect should have been enough")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	downloader := &Downloader{client: srv.Client()}
+
+	for _, path := range []string{"/t/one-hop", "/t/two-hops"} {
+		t.Run(path, func(t *testing.T) {
+			id, err := downloader.redirectedID(context.Background(), srv.URL+path, tiktokID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != "7234567890123456789" {
+				t.Errorf("id = %q", id)
+			}
+		})
+	}
+}
+
+func TestRedirectedIDWithoutVideo(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/t/gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/t/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/t/loop", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	downloader := &Downloader{client: srv.Client()}
+	for _, path := range []string{"/t/gone", "/t/loop"} {
+		t.Run(path, func(t *testing.T) {
+			if id, err := downloader.redirectedID(context.Background(), srv.URL+path, tiktokID); err == nil {
+				t.Errorf("id = %q, want an error", id)
+			}
+		})
+	}
+}
+
+func TestCleanupRemovesOnlyItsOwnDownload(t *testing.T) {
+	downloader := &Downloader{tempDir: t.TempDir()}
+
+	// Two jobs for the same video
+	var paths []string
+	for i := 0; i < 2; i++ {
+		dir, err := os.MkdirTemp(downloader.tempDir, "tiktok-7234567890123456789-*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "audio.mp3")
+		if err := os.WriteFile(path, []byte("mp3"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	outside := filepath.Join(t.TempDir(), "audio.mp3")
+	if err := os.WriteFile(outside, []byte("mp3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	downloader.cleanup(paths[0])
+	downloader.cleanup(outside)
+
+	if _, err := os.Stat(filepath.Dir(paths[0])); !os.IsNotExist(err) {
+		t.Errorf("first download still exists: %v", err)
+	}
+	if _, err := os.Stat(paths[1]); err != nil {
+		t.Errorf("second download was removed: %v", err)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the download directory was removed: %v", err)
+	}
+}
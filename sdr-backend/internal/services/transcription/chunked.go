@@ -0,0 +1,255 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Chunked splits audio that is too large for the wrapped transcriber's
+// upload limit into overlapping chunks, transcribes them concurrently and
+// stitches the results back together. Smaller files are passed straight
+// through. Chunks are copied from the source without re-encoding, so any
+// transcoding is left to Transcoded.
+type Chunked struct {
+	next         Transcriber
+	maxBytes     int64
+	chunkSeconds float64
+	overlap      float64
+	concurrency  int
+}
+
+var _ Transcriber = (*Chunked)(nil)
+
+// chunkHeadroom leaves room under the upload limit for container overhead
+// and bitrate that varies across the file
+const chunkHeadroom = 0.9
+
+// NewChunked wraps next so files over maxBytes are split into chunks of
+// chunkSeconds, each overlapping the previous one by overlapSeconds so
+// words at the cut aren't lost. At most concurrency chunks are transcribed
+// at once.
+func NewChunked(next Transcriber, maxBytes int64, chunkSeconds, overlapSeconds, concurrency int) *Chunked {
+	if overlapSeconds >= chunkSeconds {
+		overlapSeconds = chunkSeconds / 2
+	}
+	return &Chunked{
+		next:         next,
+		maxBytes:     maxBytes,
+		chunkSeconds: float64(chunkSeconds),
+		overlap:      float64(overlapSeconds),
+		concurrency:  concurrency,
+	}
+}
+
+// chunk is one slice of the source audio
+type chunk struct {
+	path       string
+	start, end float64 // seconds into the source audio
+	result     *TranscriptionResult
+}
+
+// Transcribe transcribes the file directly if it fits in one upload, and
+// chunk by chunk otherwise
+func (s *Chunked) Transcribe(ctx context.Context, audioPath string) (*TranscriptionResult, error) {
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat audio file: %w", err)
+	}
+	if info.Size() <= s.maxBytes {
+		return s.next.Transcribe(ctx, audioPath)
+	}
+
+	duration, err := probeDuration(ctx, audioPath)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "sdr-chunks-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// Copied chunks keep the source's bitrate, so shorten them if a full
+	// chunk wouldn't fit in one upload
+	chunkSeconds := s.chunkSeconds
+	if fit := float64(s.maxBytes) / (float64(info.Size()) / duration) * chunkHeadroom; fit < chunkSeconds {
+		chunkSeconds = fit
+	}
+	if chunkSeconds <= s.overlap*2 {
+		return nil, fmt.Errorf("audio bitrate is too high to split into chunks under %d bytes", s.maxBytes)
+	}
+
+	var chunks []*chunk
+	ext := filepath.Ext(audioPath)
+	step := chunkSeconds - s.overlap
+	for start := 0.0; start < duration; start += step {
+		chunks = append(chunks, &chunk{
+			path:  filepath.Join(workDir, fmt.Sprintf("chunk-%03d%s", len(chunks), ext)),
+			start: start,
+			end:   start + chunkSeconds,
+		})
+		if start+chunkSeconds >= duration {
+			break
+		}
+	}
+
+	log.Printf("Audio is %d bytes (limit %d), transcribing %.0fs in %d chunks", info.Size(), s.maxBytes, duration, len(chunks))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.concurrency)
+	for i, c := range chunks {
+		g.Go(func() error {
+			if err := s.extractChunk(gctx, audioPath, c); err != nil {
+				return fmt.Errorf("chunk %d: %w", i, err)
+			}
+			result, err := s.next.Transcribe(gctx, c.path)
+			if err != nil {
+				return fmt.Errorf("chunk %d: %w", i, err)
+			}
+			c.result = result
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return s.stitch(chunks), nil
+}
+
+// extractChunk copies the chunk out of the source audio in its original
+// format
+func (s *Chunked) extractChunk(ctx context.Context, audioPath string, c *chunk) error {
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-y",
+		"-ss", strconv.FormatFloat(c.start, 'f', 3, 64),
+		"-t", strconv.FormatFloat(c.end-c.start, 'f', 3, 64),
+		"-i", audioPath,
+		"-vn",
+		"-c:a", "copy",
+		c.path,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to split audio: %w (%s)", err, LastLine(output))
+	}
+	return nil
+}
+
+// stitch shifts each chunk's timestamps onto the source timeline and joins
+// them. Where two chunks overlap, the cut is made halfway through the
+// overlap: the earlier chunk keeps what starts before it, the later chunk
+// what starts after.
+func (s *Chunked) stitch(chunks []*chunk) *TranscriptionResult {
+	stitched := &TranscriptionResult{}
+	var texts []string
+
+	for i, c := range chunks {
+		from, until := c.start, c.end
+		if i > 0 {
+			from = c.start + s.overlap/2
+		}
+		if i < len(chunks)-1 {
+			until = chunks[i+1].start + s.overlap/2
+		}
+
+		for _, seg := range c.result.Segments {
+			seg.Start += c.start
+			seg.End += c.start
+			if seg.Start >= from && seg.Start < until {
+				stitched.Segments = append(stitched.Segments, seg)
+			}
+		}
+		for _, word := range c.result.Words {
+			word.Start += c.start
+			word.End += c.start
+			if word.Start >= from && word.Start < until {
+				stitched.Words = append(stitched.Words, word)
+			}
+		}
+		texts = append(texts, strings.TrimSpace(c.result.Text))
+	}
+
+	if len(stitched.Segments) > 0 {
+		parts := make([]string, 0, len(stitched.Segments))
+		for _, seg := range stitched.Segments {
+			if text := strings.TrimSpace(seg.Text); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		stitched.Text = strings.Join(parts, " ")
+		return stitched
+	}
+
+	// Without timestamps the overlap can only be found in the text itself
+	for _, text := range texts {
+		stitched.Text = joinOverlapping(stitched.Text, text)
+	}
+	return stitched
+}
+
+// maxOverlapWords bounds the search for text repeated across a chunk boundary
+const maxOverlapWords = 60
+
+// joinOverlapping appends b to a, dropping the longest run of words at the
+// start of b that repeats the end of a
+func joinOverlapping(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+
+	aWords, bWords := strings.Fields(a), strings.Fields(b)
+	longest := min(len(aWords), len(bWords), maxOverlapWords)
+	for n := longest; n > 0; n-- {
+		if wordsEqual(aWords[len(aWords)-n:], bWords[:n]) {
+			return strings.Join(append(aWords, bWords[n:]...), " ")
+		}
+	}
+	return a + " " + b
+}
+
+// wordsEqual compares words ignoring case and surrounding punctuation, which
+// Whisper often changes at a cut
+func wordsEqual(a, b []string) bool {
+	for i := range a {
+		if normalizeWord(a[i]) != normalizeWord(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.Trim(word, `.,!?;:"'()-`))
+}
+
+// probeDuration returns the length of the audio file in seconds
+func probeDuration(ctx context.Context, audioPath string) (float64, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		audioPath,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe audio duration: %w", err)
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse audio duration %q: %w", strings.TrimSpace(string(output)), err)
+	}
+	return duration, nil
+}
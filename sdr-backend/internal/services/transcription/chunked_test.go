@@ -0,0 +1,87 @@
+package transcription
+
+import "testing"
+
+func TestJoinOverlapping(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"empty first", "", "set the cutoff", "set the cutoff"},
+		{"empty second", "set the cutoff", "", "set the cutoff"},
+		{"no overlap", "load wavetable", "set the cutoff", "load wavetable set the cutoff"},
+		{"overlap", "then turn the cutoff down", "the cutoff down to 800", "then turn the cutoff down to 800"},
+		{"case and punctuation", "turn the Cutoff down.", "cutoff down to 800", "turn the Cutoff down. to 800"},
+		{"whole second repeated", "add some reverb", "some reverb", "add some reverb"},
+		{"longest overlap wins", "up up up", "up up up and away", "up up up and away"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinOverlapping(tt.a, tt.b); got != tt.want {
+				t.Errorf("joinOverlapping(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStitch(t *testing.T) {
+	s := &Chunked{chunkSeconds: 60, overlap: 10}
+
+	// The chunks overlap from 50s to 60s, so the cut is at 55s
+	chunks := []*chunk{
+		{start: 0, end: 60, result: &TranscriptionResult{
+			Segments: []Segment{
+				{Start: 0, End: 20, Text: "one"},
+				{Start: 40, End: 54, Text: "two"},
+				{Start: 54, End: 58, Text: "three"},
+				{Start: 57, End: 60, Text: "fou"}, // cut off, the next chunk has it
+			},
+			Words: []Word{{Start: 54, End: 55, Word: "three"}, {Start: 57, End: 58, Word: "fou"}},
+		}},
+		{start: 50, end: 110, result: &TranscriptionResult{
+			Segments: []Segment{
+				{Start: 4, End: 8, Text: "three"},
+				{Start: 7, End: 10, Text: "four"},
+				{Start: 30, End: 40, Text: "five"},
+			},
+			Words: []Word{{Start: 4, End: 5, Word: "three"}, {Start: 7, End: 8, Word: "four"}},
+		}},
+	}
+
+	got := s.stitch(chunks)
+
+	if got.Text != "one two three four five" {
+		t.Errorf("Text = %q", got.Text)
+	}
+
+	wantStarts := []float64{0, 40, 54, 57, 80}
+	if len(got.Segments) != len(wantStarts) {
+		t.Fatalf("got %d segments %+v, want %d", len(got.Segments), got.Segments, len(wantStarts))
+	}
+	for i, want := range wantStarts {
+		if got.Segments[i].Start != want {
+			t.Errorf("segment %d starts at %g, want %g", i, got.Segments[i].Start, want)
+		}
+	}
+	if got.Segments[3].Text != "four" {
+		t.Errorf("segment 3 = %q, want the later chunk's \"four\"", got.Segments[3].Text)
+	}
+
+	if len(got.Words) != 2 || got.Words[0].Word != "three" || got.Words[1].Word != "four" || got.Words[1].Start != 57 {
+		t.Errorf("Words = %+v", got.Words)
+	}
+}
+
+func TestStitchWithoutSegments(t *testing.T) {
+	s := &Chunked{chunkSeconds: 60, overlap: 10}
+	chunks := []*chunk{
+		{start: 0, end: 60, result: &TranscriptionResult{Text: "load wavetable and set the cutoff"}},
+		{start: 50, end: 110, result: &TranscriptionResult{Text: " set the cutoff to 800 hertz"}},
+	}
+
+	if got := s.stitch(chunks).Text; got != "load wavetable and set the cutoff to 800 hertz" {
+		t.Errorf("Text = %q", got)
+	}
+}
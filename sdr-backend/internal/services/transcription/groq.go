@@ -0,0 +1,119 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const groqAPIURL = "https://api.groq.com/openai/v1/audio/transcriptions"
+
+// Groq transcribes audio with Whisper through Groq's OpenAI-compatible API
+type Groq struct {
+	apiKey string
+	client *http.Client
+}
+
+var _ Transcriber = (*Groq)(nil)
+
+// NewGroq creates a new Groq transcriber
+func NewGroq(apiKey string) *Groq {
+	return &Groq{
+		apiKey: apiKey,
+		client: &http.Client{},
+	}
+}
+
+// Transcribe streams audio to Groq Whisper and returns the transcription.
+// The multipart body is written through a pipe as it's uploaded, so the file
+// is never held in memory.
+func (s *Groq) Transcribe(ctx context.Context, audioPath string) (*TranscriptionResult, error) {
+	// Open the audio file
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	// Closing the read side unblocks the writer if the upload stops early
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeForm(writer, file, filepath.Base(audioPath)))
+	}()
+
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, "POST", groqAPIURL, pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	// Send request
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("groq API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var result TranscriptionResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// writeForm writes the transcription request fields followed by the audio
+// file, then closes the multipart writer
+func writeForm(writer *multipart.Writer, file io.Reader, filename string) error {
+	// Add model field - using whisper-large-v3-turbo for speed and accuracy
+	if err := writer.WriteField("model", "whisper-large-v3-turbo"); err != nil {
+		return fmt.Errorf("failed to write model field: %w", err)
+	}
+
+	// Add response format
+	// verbose_json includes segment timestamps; words are only returned
+	// when explicitly requested
+	if err := writer.WriteField("response_format", "verbose_json"); err != nil {
+		return fmt.Errorf("failed to write response_format field: %w", err)
+	}
+
+	for _, granularity := range []string{"segment", "word"} {
+		if err := writer.WriteField("timestamp_granularities[]", granularity); err != nil {
+			return fmt.Errorf("failed to write timestamp_granularities field: %w", err)
+		}
+	}
+
+	// Add the audio file
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to copy file data: %w", err)
+	}
+
+	return writer.Close()
+}
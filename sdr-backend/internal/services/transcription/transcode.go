@@ -0,0 +1,54 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Transcoded re-encodes audio as mono 16 kHz Opus before handing it to the
+// wrapped transcriber. Whisper resamples to 16 kHz mono anyway, so this
+// shrinks the upload several times over without hurting accuracy.
+type Transcoded struct {
+	next    Transcriber
+	bitrate string
+}
+
+var _ Transcriber = (*Transcoded)(nil)
+
+// NewTranscoded wraps next so audio is transcoded to Opus at bitrate (an
+// ffmpeg bitrate such as "24k") first
+func NewTranscoded(next Transcriber, bitrate string) *Transcoded {
+	return &Transcoded{
+		next:    next,
+		bitrate: bitrate,
+	}
+}
+
+// Transcribe transcodes the audio to a temp file and transcribes that
+func (s *Transcoded) Transcribe(ctx context.Context, audioPath string) (*TranscriptionResult, error) {
+	workDir, err := os.MkdirTemp("", "sdr-transcode-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	outputPath := filepath.Join(workDir, "audio.ogg")
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-y", "-i", audioPath,
+		"-vn",
+		"-ac", "1",
+		"-ar", "16000",
+		"-c:a", "libopus",
+		"-b:a", s.bitrate,
+		"-application", "voip", // tuned for speech
+		outputPath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to transcode audio: %w (%s)", err, LastLine(output))
+	}
+
+	return s.next.Transcribe(ctx, outputPath)
+}
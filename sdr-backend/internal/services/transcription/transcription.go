@@ -1,103 +1,31 @@
 package transcription
 
-import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"mime/multipart"
-	"net/http"
-	"os"
-	"path/filepath"
-)
+import "context"
 
-const groqAPIURL = "https://api.groq.com/openai/v1/audio/transcriptions"
-
-// Service handles audio transcription via Groq
-type Service struct {
-	apiKey string
-	client *http.Client
-}
-
-// NewService creates a new transcription service
-func NewService(apiKey string) *Service {
-	return &Service{
-		apiKey: apiKey,
-		client: &http.Client{},
-	}
+// Transcriber converts an audio file into text
+type Transcriber interface {
+	Transcribe(ctx context.Context, audioPath string) (*TranscriptionResult, error)
 }
 
-// TranscriptionResult contains the transcribed text
+// TranscriptionResult contains the transcribed text and, when the provider
+// supports it, where in the audio each part was spoken. Field names follow
+// Whisper's verbose_json response so it can be decoded directly.
 type TranscriptionResult struct {
-	Text string `json:"text"`
+	Text     string    `json:"text"`
+	Segments []Segment `json:"segments,omitempty"`
+	Words    []Word    `json:"words,omitempty"`
 }
 
-// Transcribe sends audio to Groq Whisper and returns the transcription
-func (s *Service) Transcribe(audioPath string) (*TranscriptionResult, error) {
-	// Open the audio file
-	file, err := os.Open(audioPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open audio file: %w", err)
-	}
-	defer file.Close()
-
-	// Create multipart form
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	// Add the audio file
-	part, err := writer.CreateFormFile("file", filepath.Base(audioPath))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-	
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("failed to copy file data: %w", err)
-	}
-
-	// Add model field - using whisper-large-v3-turbo for speed and accuracy
-	if err := writer.WriteField("model", "whisper-large-v3-turbo"); err != nil {
-		return nil, fmt.Errorf("failed to write model field: %w", err)
-	}
-
-	// Add response format
-	if err := writer.WriteField("response_format", "json"); err != nil {
-		return nil, fmt.Errorf("failed to write response_format field: %w", err)
-	}
-
-	writer.Close()
-
-	// Create request
-	req, err := http.NewRequest("POST", groqAPIURL, &buf)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+s.apiKey)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	// Send request
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("groq API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	// Parse response
-	var result TranscriptionResult
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
+// Segment is a sentence-sized stretch of the transcription
+type Segment struct {
+	Start float64 `json:"start"` // seconds
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
 
-	return &result, nil
+// Word is a single transcribed word
+type Word struct {
+	Start float64 `json:"start"` // seconds
+	End   float64 `json:"end"`
+	Word  string  `json:"word"`
 }
@@ -0,0 +1,115 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// WhisperCpp transcribes audio locally by shelling out to a whisper.cpp
+// binary, so no audio leaves the machine and no network is needed
+type WhisperCpp struct {
+	binary    string
+	modelPath string
+	language  string
+}
+
+var _ Transcriber = (*WhisperCpp)(nil)
+
+// NewWhisperCpp creates a transcriber for the whisper.cpp CLI (whisper-cli,
+// or main in older builds) and a ggml model file
+func NewWhisperCpp(binary, modelPath, language string) *WhisperCpp {
+	return &WhisperCpp{
+		binary:    binary,
+		modelPath: modelPath,
+		language:  language,
+	}
+}
+
+// whisperCppOutput is the file written by whisper.cpp's --output-json flag
+type whisperCppOutput struct {
+	Transcription []struct {
+		Offsets struct {
+			From int `json:"from"` // milliseconds
+			To   int `json:"to"`
+		} `json:"offsets"`
+		Text string `json:"text"`
+	} `json:"transcription"`
+}
+
+// Transcribe converts the audio to 16 kHz mono WAV, which whisper.cpp
+// requires, and runs the model over it
+func (s *WhisperCpp) Transcribe(ctx context.Context, audioPath string) (*TranscriptionResult, error) {
+	workDir, err := os.MkdirTemp("", "sdr-whisper-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	wavPath := filepath.Join(workDir, "audio.wav")
+	convertCmd := exec.CommandContext(ctx, "ffmpeg",
+		"-y", "-i", audioPath,
+		"-ar", "16000", // whisper.cpp only accepts 16 kHz
+		"-ac", "1",
+		"-c:a", "pcm_s16le",
+		wavPath,
+	)
+	if output, err := convertCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to convert audio: %w (%s)", err, LastLine(output))
+	}
+
+	outputBase := filepath.Join(workDir, "transcript")
+	var stderr bytes.Buffer
+	whisperCmd := exec.CommandContext(ctx, s.binary,
+		"--model", s.modelPath,
+		"--file", wavPath,
+		"--language", s.language,
+		"--output-json",
+		"--output-file", outputBase,
+		"--no-prints",
+	)
+	whisperCmd.Stderr = &stderr
+	if err := whisperCmd.Run(); err != nil {
+		return nil, fmt.Errorf("whisper.cpp failed: %w (%s)", err, LastLine(stderr.Bytes()))
+	}
+
+	data, err := os.ReadFile(outputBase + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read whisper.cpp output: %w", err)
+	}
+
+	var output whisperCppOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse whisper.cpp output: %w", err)
+	}
+
+	result := &TranscriptionResult{}
+	parts := make([]string, 0, len(output.Transcription))
+	for _, segment := range output.Transcription {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		parts = append(parts, text)
+		result.Segments = append(result.Segments, Segment{
+			Start: float64(segment.Offsets.From) / 1000,
+			End:   float64(segment.Offsets.To) / 1000,
+			Text:  text,
+		})
+	}
+	result.Text = strings.Join(parts, " ")
+
+	return result, nil
+}
+
+// LastLine returns the final non-empty line of command output, which is
+// usually the error message
+func LastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,188 @@
+package units
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+// Confidence levels for parsed values
+const (
+	exact       = 1.0 // number and recognized unit
+	noUnit      = 0.8 // number without a unit
+	approximate = 0.7 // "about 2k", "~40%"
+	vague       = 0.5 // "halfway", "all the way up"
+)
+
+// unit is a recognized unit spelling and how to convert it to the
+// canonical unit
+type unit struct {
+	canonical string
+	scale     float64
+}
+
+// unitSpellings maps lowercase unit text to its canonical unit
+var unitSpellings = map[string]unit{
+	"hz":           {models.UnitHertz, 1},
+	"hertz":        {models.UnitHertz, 1},
+	"k":            {models.UnitHertz, 1000},
+	"khz":          {models.UnitHertz, 1000},
+	"kilohertz":    {models.UnitHertz, 1000},
+	"db":           {models.UnitDecibels, 1},
+	"decibel":      {models.UnitDecibels, 1},
+	"decibels":     {models.UnitDecibels, 1},
+	"%":            {models.UnitPercent, 1},
+	"percent":      {models.UnitPercent, 1},
+	"pct":          {models.UnitPercent, 1},
+	"ms":           {models.UnitMillis, 1},
+	"msec":         {models.UnitMillis, 1},
+	"millisecond":  {models.UnitMillis, 1},
+	"milliseconds": {models.UnitMillis, 1},
+	"s":            {models.UnitMillis, 1000},
+	"sec":          {models.UnitMillis, 1000},
+	"secs":         {models.UnitMillis, 1000},
+	"second":       {models.UnitMillis, 1000},
+	"seconds":      {models.UnitMillis, 1000},
+	"st":           {models.UnitSemitones, 1},
+	"semi":         {models.UnitSemitones, 1},
+	"semis":        {models.UnitSemitones, 1},
+	"semitone":     {models.UnitSemitones, 1},
+	"semitones":    {models.UnitSemitones, 1},
+	"oct":          {models.UnitSemitones, 12},
+	"octave":       {models.UnitSemitones, 12},
+	"octaves":      {models.UnitSemitones, 12},
+	"ct":           {models.UnitCents, 1},
+	"cent":         {models.UnitCents, 1},
+	"cents":        {models.UnitCents, 1},
+	"bpm":          {models.UnitBPM, 1},
+}
+
+// vagueValues are positions people describe instead of numbers, as a
+// percentage of the control's range
+var vagueValues = []struct {
+	phrase  *regexp.Regexp
+	percent float64
+}{
+	{wordPattern("all the way up"), 100},
+	{wordPattern("all the way down"), 0},
+	{wordPattern("fully up"), 100},
+	{wordPattern("fully down"), 0},
+	{wordPattern("maxed"), 100},
+	{wordPattern("maximum"), 100},
+	{wordPattern("max"), 100},
+	{wordPattern("full"), 100},
+	{wordPattern("minimum"), 0},
+	{wordPattern("min"), 0},
+	{wordPattern("zero"), 0},
+	{wordPattern("off"), 0},
+	{wordPattern("halfway"), 50},
+	{wordPattern("half"), 50},
+	{wordPattern("noon"), 50},
+	{wordPattern("three quarters"), 75},
+	{wordPattern("quarter"), 25},
+}
+
+// wordPattern matches phrase as whole words
+func wordPattern(phrase string) *regexp.Regexp {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(phrase) + `\b`)
+}
+
+var (
+	approximateWords = regexp.MustCompile(`\b(about|around|roughly|approximately|approx|like|maybe|somewhere)\b|~`)
+	thousandsPattern = regexp.MustCompile(`(\d),(\d{3})\b`)
+	clockPattern     = regexp.MustCompile(`^(\d{1,2})\s*o'?\s*clock$`)
+	ratioPattern     = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*:\s*1$`)
+	notePattern      = regexp.MustCompile(`^1\s*/\s*(\d+)(t|d)?$`)
+	numberPattern    = regexp.MustCompile(`([+-]?(?:\d+(?:\.\d+)?|\.\d+))\s*(%|[a-z]+)?`)
+)
+
+// Normalize parses every value in a parser's parameter map
+func Normalize(params map[string]string) map[string]models.ParameterValue {
+	if len(params) == 0 {
+		return nil
+	}
+
+	values := make(map[string]models.ParameterValue, len(params))
+	for key, raw := range params {
+		values[key] = Parse(raw)
+	}
+	return values
+}
+
+// Parse reads a number and unit out of a parameter value such as "2.5kHz",
+// "-6 dB", "about 40%", "1/16" or "halfway", converting to the canonical
+// units in models (kHz to Hz, seconds to ms, octaves to semitones). Values
+// that can't be read keep only their raw text, with zero confidence.
+func Parse(raw string) models.ParameterValue {
+	result := models.ParameterValue{Raw: raw}
+
+	text := strings.ToLower(strings.TrimSpace(raw))
+	text = strings.NewReplacer("−", "-", "–", "-", "’", "'").Replace(text)
+	text = thousandsPattern.ReplaceAllString(text, "$1$2")
+	text = strings.ReplaceAll(text, ",", ".") // decimal comma
+
+	confidence := exact
+	if approximateWords.MatchString(text) {
+		text = strings.TrimSpace(approximateWords.ReplaceAllString(text, ""))
+		confidence = approximate
+	}
+
+	// Knob positions: 7 o'clock is fully down, 12 is the middle and 5 is
+	// fully up
+	if m := clockPattern.FindStringSubmatch(text); m != nil {
+		hour, _ := strconv.Atoi(m[1])
+		if hour < 1 || hour > 12 {
+			return result
+		}
+		percent := float64((hour+5)%12) * 10
+		return withValue(result, min(percent, 100), models.UnitPercent, vague)
+	}
+
+	if m := ratioPattern.FindStringSubmatch(text); m != nil {
+		value, _ := strconv.ParseFloat(m[1], 64)
+		return withValue(result, value, models.UnitRatio, confidence)
+	}
+
+	if m := notePattern.FindStringSubmatch(text); m != nil {
+		division, _ := strconv.ParseFloat(m[1], 64)
+		if division == 0 {
+			return result
+		}
+		value := 1 / division
+		switch m[2] {
+		case "t": // triplet
+			value *= 2.0 / 3
+		case "d": // dotted
+			value *= 1.5
+		}
+		return withValue(result, value, models.UnitNote, confidence)
+	}
+
+	if m := numberPattern.FindStringSubmatch(text); m != nil {
+		value, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return result
+		}
+		if u, ok := unitSpellings[m[2]]; ok {
+			return withValue(result, value*u.scale, u.canonical, confidence)
+		}
+		return withValue(result, value, "", min(confidence, noUnit))
+	}
+
+	for _, v := range vagueValues {
+		if v.phrase.MatchString(text) {
+			return withValue(result, v.percent, models.UnitPercent, vague)
+		}
+	}
+
+	return result
+}
+
+func withValue(result models.ParameterValue, value float64, unit string, confidence float64) models.ParameterValue {
+	result.Value = &value
+	result.Unit = unit
+	result.Confidence = confidence
+	return result
+}
@@ -0,0 +1,93 @@
+package units
+
+import (
+	"math"
+	"testing"
+
+	"github.com/camwick/sdr-backend/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw        string
+		value      float64
+		unit       string
+		confidence float64
+	}{
+		{"2.5kHz", 2500, models.UnitHertz, exact},
+		{"2,5 kHz", 2500, models.UnitHertz, exact},
+		{"1,000 Hz", 1000, models.UnitHertz, exact},
+		{"about 2k", 2000, models.UnitHertz, approximate},
+		{"-6 dB", -6, models.UnitDecibels, exact},
+		{"−3 dB", -3, models.UnitDecibels, exact},
+		{"about 40%", 40, models.UnitPercent, approximate},
+		{"~40%", 40, models.UnitPercent, approximate},
+		{"0.5 s", 500, models.UnitMillis, exact},
+		{"1 octave", 12, models.UnitSemitones, exact},
+		{"+7 semitones", 7, models.UnitSemitones, exact},
+		{"4:1", 4, models.UnitRatio, exact},
+		{"1/16", 0.0625, models.UnitNote, exact},
+		{"1/16t", 0.0625 * 2 / 3, models.UnitNote, exact},
+		{"1/8d", 0.1875, models.UnitNote, exact},
+		{"3 o'clock", 80, models.UnitPercent, vague},
+		{"12 o'clock", 50, models.UnitPercent, vague},
+		{"7 oclock", 0, models.UnitPercent, vague},
+		{"5 o’clock", 100, models.UnitPercent, vague},
+		{"halfway", 50, models.UnitPercent, vague},
+		{"all the way up", 100, models.UnitPercent, vague},
+		{"50", 50, "", noUnit},
+		{"about 50", 50, "", approximate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got := Parse(tt.raw)
+			if got.Raw != tt.raw {
+				t.Errorf("Raw = %q, want %q", got.Raw, tt.raw)
+			}
+			if got.Value == nil {
+				t.Fatalf("Value = nil, want %g", tt.value)
+			}
+			if math.Abs(*got.Value-tt.value) > 1e-9 {
+				t.Errorf("Value = %g, want %g", *got.Value, tt.value)
+			}
+			if got.Unit != tt.unit {
+				t.Errorf("Unit = %q, want %q", got.Unit, tt.unit)
+			}
+			if got.Confidence != tt.confidence {
+				t.Errorf("Confidence = %g, want %g", got.Confidence, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestParseUnreadable(t *testing.T) {
+	for _, raw := range []string{"", "wide", "13 o'clock", "1/0"} {
+		t.Run(raw, func(t *testing.T) {
+			got := Parse(raw)
+			if got.Value != nil || got.Unit != "" || got.Confidence != 0 {
+				t.Errorf("Parse(%q) = %+v, want only the raw text", raw, got)
+			}
+			if got.Raw != raw {
+				t.Errorf("Raw = %q, want %q", got.Raw, raw)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize(nil); got != nil {
+		t.Errorf("Normalize(nil) = %v, want nil", got)
+	}
+
+	got := Normalize(map[string]string{"Cutoff": "2k", "Mix": "wide"})
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	if v := got["Cutoff"].Value; v == nil || *v != 2000 {
+		t.Errorf("Cutoff = %+v, want 2000", got["Cutoff"])
+	}
+	if got["Mix"].Value != nil {
+		t.Errorf("Mix = %+v, want no value", got["Mix"])
+	}
+}
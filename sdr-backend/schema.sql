@@ -1,5 +1,7 @@
 -- SDR (Sound Design Recipes) Database Schema
--- Run this in Supabase SQL Editor
+-- Run this in Supabase SQL Editor to bootstrap a new project.
+-- Schema changes are versioned in internal/migrations; apply them with
+-- `sdr-backend migrate up` and keep this file in sync.
 
 -- Enable UUID extension
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
@@ -7,25 +9,29 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 -- Creators table
 CREATE TABLE creators (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    tiktok_handle VARCHAR(255) UNIQUE NOT NULL,
+    platform VARCHAR(50) NOT NULL, -- tiktok, youtube, instagram or upload
+    handle VARCHAR(255) NOT NULL,
     display_name VARCHAR(255) NOT NULL,
     avatar_url TEXT,
     is_claimed BOOLEAN DEFAULT FALSE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (platform, handle)
 );
 
 -- Tutorials table
 CREATE TABLE tutorials (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     creator_id UUID NOT NULL REFERENCES creators(id) ON DELETE CASCADE,
-    tiktok_url TEXT NOT NULL,
-    tiktok_video_id VARCHAR(255) UNIQUE NOT NULL,
+    platform VARCHAR(50) NOT NULL, -- tiktok, youtube, instagram or upload
+    source_url TEXT NOT NULL,
+    video_id VARCHAR(255) NOT NULL,
     title VARCHAR(500) NOT NULL,
     sound_type VARCHAR(100) NOT NULL,
     raw_transcription TEXT NOT NULL,
     status VARCHAR(50) DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (platform, video_id)
 );
 
 -- Instructions table
@@ -37,21 +43,33 @@ CREATE TABLE instructions (
     ableton_device VARCHAR(255),
     parameters JSONB DEFAULT '{}',
     notes TEXT,
-    screenshot_url TEXT
+    screenshot_url TEXT,
+    start_time DOUBLE PRECISION, -- seconds into the video where the step is explained
+    end_time DOUBLE PRECISION
+);
+
+-- Timestamped transcript segments
+CREATE TABLE transcript_segments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tutorial_id UUID NOT NULL REFERENCES tutorials(id) ON DELETE CASCADE,
+    segment_index INTEGER NOT NULL,
+    start_time DOUBLE PRECISION NOT NULL,
+    end_time DOUBLE PRECISION NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (tutorial_id, segment_index)
 );
 
 -- Indexes for performance
 CREATE INDEX idx_tutorials_creator_id ON tutorials(creator_id);
 CREATE INDEX idx_tutorials_status ON tutorials(status);
 CREATE INDEX idx_tutorials_sound_type ON tutorials(sound_type);
-CREATE INDEX idx_tutorials_video_id ON tutorials(tiktok_video_id);
 CREATE INDEX idx_instructions_tutorial_id ON instructions(tutorial_id);
-CREATE INDEX idx_creators_handle ON creators(tiktok_handle);
 
 -- Row Level Security (RLS) - Enable for production
 ALTER TABLE creators ENABLE ROW LEVEL SECURITY;
 ALTER TABLE tutorials ENABLE ROW LEVEL SECURITY;
 ALTER TABLE instructions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE transcript_segments ENABLE ROW LEVEL SECURITY;
 
 -- Public read access policies
 CREATE POLICY "Public can view creators" ON creators
@@ -69,6 +87,15 @@ CREATE POLICY "Public can view instructions" ON instructions
         )
     );
 
+CREATE POLICY "Public can view transcript segments" ON transcript_segments
+    FOR SELECT USING (
+        EXISTS (
+            SELECT 1 FROM tutorials
+            WHERE tutorials.id = transcript_segments.tutorial_id
+            AND tutorials.status = 'approved'
+        )
+    );
+
 -- Service role can do everything (for backend)
 CREATE POLICY "Service role full access to creators" ON creators
     FOR ALL USING (auth.role() = 'service_role');
@@ -79,6 +106,9 @@ CREATE POLICY "Service role full access to tutorials" ON tutorials
 CREATE POLICY "Service role full access to instructions" ON instructions
     FOR ALL USING (auth.role() = 'service_role');
 
+CREATE POLICY "Service role full access to transcript segments" ON transcript_segments
+    FOR ALL USING (auth.role() = 'service_role');
+
 -- Anon key can insert (for transcription submissions)
 CREATE POLICY "Anon can insert creators" ON creators
     FOR INSERT WITH CHECK (true);
@@ -89,6 +119,9 @@ CREATE POLICY "Anon can insert tutorials" ON tutorials
 CREATE POLICY "Anon can insert instructions" ON instructions
     FOR INSERT WITH CHECK (true);
 
+CREATE POLICY "Anon can insert transcript segments" ON transcript_segments
+    FOR INSERT WITH CHECK (true);
+
 -- Updated_at trigger
 CREATE OR REPLACE FUNCTION update_updated_at()
 RETURNS TRIGGER AS $$
@@ -102,3 +135,80 @@ CREATE TRIGGER tutorials_updated_at
     BEFORE UPDATE ON tutorials
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_at();
+
+-- Atomic tutorial + instructions + transcript segments insert, called via
+-- PostgREST RPC. SECURITY INVOKER so the insert policies above apply.
+CREATE OR REPLACE FUNCTION create_tutorial_with_instructions(p_tutorial JSONB, p_instructions JSONB, p_segments JSONB DEFAULT '[]')
+RETURNS tutorials
+LANGUAGE plpgsql
+SECURITY INVOKER
+SET search_path = public
+AS $$
+DECLARE
+    saved tutorials;
+BEGIN
+    saved.id := gen_random_uuid();
+    saved.creator_id := (p_tutorial->>'creator_id')::UUID;
+    saved.platform := p_tutorial->>'platform';
+    saved.source_url := p_tutorial->>'source_url';
+    saved.video_id := p_tutorial->>'video_id';
+    saved.title := p_tutorial->>'title';
+    saved.sound_type := p_tutorial->>'sound_type';
+    saved.raw_transcription := p_tutorial->>'raw_transcription';
+    saved.status := 'pending';
+    saved.created_at := NOW();
+    saved.updated_at := saved.created_at;
+
+    INSERT INTO tutorials (id, creator_id, platform, source_url, video_id, title, sound_type, raw_transcription, status, created_at, updated_at)
+    VALUES (saved.id, saved.creator_id, saved.platform, saved.source_url, saved.video_id, saved.title,
+            saved.sound_type, saved.raw_transcription, saved.status, saved.created_at, saved.updated_at);
+
+    INSERT INTO instructions (tutorial_id, step_number, description, ableton_device, parameters, notes, start_time, end_time)
+    SELECT saved.id, i.step_number, i.description, i.ableton_device, COALESCE(i.parameters, '{}'), i.notes, i.start_time, i.end_time
+    FROM jsonb_to_recordset(COALESCE(p_instructions, '[]'::JSONB))
+        AS i(step_number INTEGER, description TEXT, ableton_device VARCHAR(255), parameters JSONB, notes TEXT,
+             start_time DOUBLE PRECISION, end_time DOUBLE PRECISION);
+
+    INSERT INTO transcript_segments (tutorial_id, segment_index, start_time, end_time, text)
+    SELECT saved.id, s.segment_index, s.start_time, s.end_time, s.text
+    FROM jsonb_to_recordset(COALESCE(p_segments, '[]'::JSONB))
+        AS s(segment_index INTEGER, start_time DOUBLE PRECISION, end_time DOUBLE PRECISION, text TEXT);
+
+    RETURN saved;
+END;
+$$;
+
+GRANT EXECUTE ON FUNCTION create_tutorial_with_instructions(JSONB, JSONB, JSONB) TO PUBLIC;
+
+-- Tutorial ID for a video whatever its status, for duplicate checks that
+-- the select policies would otherwise hide pending tutorials from
+CREATE OR REPLACE FUNCTION tutorial_id_for_video(p_platform TEXT, p_video_id TEXT)
+RETURNS UUID
+LANGUAGE sql
+STABLE
+SECURITY DEFINER
+SET search_path = public
+AS $$
+    SELECT id FROM tutorials WHERE platform = p_platform AND video_id = p_video_id;
+$$;
+
+GRANT EXECUTE ON FUNCTION tutorial_id_for_video(TEXT, TEXT) TO PUBLIC;
+
+-- Tutorials per device, called via PostgREST RPC. SECURITY INVOKER so row
+-- level security hides pending and rejected tutorials from the anon key.
+CREATE OR REPLACE FUNCTION device_recipe_counts(p_status TEXT DEFAULT '')
+RETURNS TABLE (ableton_device VARCHAR(255), recipe_count BIGINT)
+LANGUAGE sql
+STABLE
+SECURITY INVOKER
+SET search_path = public
+AS $$
+    SELECT i.ableton_device, COUNT(DISTINCT i.tutorial_id)
+    FROM instructions i
+    JOIN tutorials t ON t.id = i.tutorial_id
+    WHERE (p_status = '' OR t.status = p_status)
+    AND COALESCE(i.ableton_device, '') <> ''
+    GROUP BY i.ableton_device;
+$$;
+
+GRANT EXECUTE ON FUNCTION device_recipe_counts(TEXT) TO PUBLIC;